@@ -0,0 +1,280 @@
+// Command pattern-export renders the tester's patterns to PNG files for
+// displays that can only show pictures, such as TVs playing from a USB
+// stick. It draws into an invisible window, so nothing appears on screen,
+// but it still needs a display and OpenGL; on a headless machine run it
+// under a virtual X server:
+//
+//	pattern-export -res 1080p,4k -out patterns
+//	pattern-export -tests "checkerboard size=4; zone-plate mode=circular" -res 8k
+//	xvfb-run -s "-screen 0 3840x2160x24" pattern-export -res 4k
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/frametime"
+	"github.com/keshon/screen-tester/internal/playlist"
+	"github.com/keshon/screen-tester/internal/screenshot"
+	_ "github.com/keshon/screen-tester/internal/tests" // auto-register tests
+	"github.com/keshon/screen-tester/internal/version"
+)
+
+var (
+	outDir     = flag.String("out", "patterns", "directory the PNG files and index.csv are written to")
+	resList    = flag.String("res", "1080p", "comma-separated resolutions: 720p, 1080p, 1440p, 4k, 8k or WIDTHxHEIGHT")
+	testSpec   = flag.String("tests", "", `tests to export with their options, in -playlist syntax without durations, e.g. "checkerboard size=4; zone-plate mode=circular" (default: all tests)`)
+	brightness = flag.Float64("brightness", 1, "brightness from 0 to 1")
+	frames     = flag.Int("frames", 3, "frames each test runs before it is saved, so animated tests and loaded images settle")
+	imagePath  = flag.String("images", "images", "directory or file with PNG/JPEG images for the User Images test")
+)
+
+// namedResolutions are the -res shorthands.
+var namedResolutions = map[string][2]int{
+	"720p":  {1280, 720},
+	"1080p": {1920, 1080},
+	"1440p": {2560, 1440},
+	"4k":    {3840, 2160},
+	"8k":    {7680, 4320},
+}
+
+type resolution struct {
+	Label         string
+	Width, Height int
+}
+
+func parseResolutions(list string) ([]resolution, error) {
+	var res []resolution
+	for _, field := range strings.Split(list, ",") {
+		label := strings.ToLower(strings.TrimSpace(field))
+		if size, ok := namedResolutions[label]; ok {
+			res = append(res, resolution{label, size[0], size[1]})
+			continue
+		}
+		w, h, ok := strings.Cut(label, "x")
+		width, errW := strconv.Atoi(w)
+		height, errH := strconv.Atoi(h)
+		if !ok || errW != nil || errH != nil || width <= 0 || height <= 0 {
+			return nil, fmt.Errorf("unknown resolution %q", field)
+		}
+		res = append(res, resolution{label, width, height})
+	}
+	return res, nil
+}
+
+// displayHint is printed when no window can be opened.
+const displayHint = `pattern-export needs a display and OpenGL; on a headless machine run it under xvfb-run`
+
+// windowOpen is set once the window exists; panics before that are
+// reported as a missing display.
+var windowOpen atomic.Bool
+
+// run does the export and returns the exit code. It returns rather than
+// exiting so the deferred cleanup runs.
+func run() int {
+	resolutions, err := parseResolutions(*resList)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "res:", err)
+		return 2
+	}
+	list := playlist.Default(core.AllTests(), 0)
+	if *testSpec != "" {
+		list, err = playlist.Parse(*testSpec, false)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tests:", err)
+			return 2
+		}
+	}
+
+	first := resolutions[0]
+	win, err := pixelgl.NewWindow(pixelgl.WindowConfig{
+		Title:     version.AppFullName + " pattern export",
+		Bounds:    pixel.R(0, 0, float64(first.Width), float64(first.Height)),
+		Invisible: true,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "window:", err)
+		fmt.Fprintln(os.Stderr, displayHint)
+		return 1
+	}
+	windowOpen.Store(true)
+	defer win.Destroy()
+
+	ctx := &core.WindowContext{
+		Win:        win,
+		Brightness: core.Clamp(*brightness, 0, 1),
+		ImagePath:  *imagePath,
+		Frames:     frametime.NewRecorder(240, 60),
+	}
+
+	// Every step starts from the defaults, so options don't carry over
+	// from one step or resolution to the next.
+	defaults := map[core.ScreenTest]map[string]string{}
+	for _, step := range list.Steps {
+		defaults[step.Test] = core.Settings(step.Test)
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	index, err := os.Create(filepath.Join(*outDir, "index.csv"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer index.Close()
+	rows := csv.NewWriter(index)
+	rows.Write([]string{"file", "resolution", "test", "name", "options", "brightness"})
+
+	ok := true
+	for _, res := range resolutions {
+		if !render(ctx, res, list, defaults, rows) {
+			ok = false
+		}
+	}
+	rows.Flush()
+	if err := rows.Error(); err != nil {
+		fmt.Fprintln(os.Stderr, "index:", err)
+		ok = false
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+// render saves every step at one resolution into a subdirectory named
+// after it. Files are numbered so players that sort by name keep the order.
+// A step that fails is reported and skipped; render reports whether all
+// of them were saved.
+func render(ctx *core.WindowContext, res resolution, list *playlist.Playlist, defaults map[core.ScreenTest]map[string]string, rows *csv.Writer) bool {
+	win := ctx.Win
+	win.SetBounds(pixel.R(0, 0, float64(res.Width), float64(res.Height)))
+	win.Update() // resizes the canvas to the new framebuffer
+	ctx.ScreenWidth, ctx.ScreenHeight = res.Width, res.Height
+
+	dir := filepath.Join(*outDir, res.Label)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", res.Label, err)
+		return false
+	}
+
+	ok := true
+	for i, step := range list.Steps {
+		name, err := renderStep(ctx, res, dir, i, step, defaults[step.Test], rows)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: step %d (%s): %v\n", res.Label, i+1, core.TestID(step.Test), err)
+			ok = false
+			continue
+		}
+		fmt.Println(name)
+	}
+	return ok
+}
+
+// renderStep saves step i as a file in dir and returns its path.
+func renderStep(ctx *core.WindowContext, res resolution, dir string, i int, step playlist.Step, defaults map[string]string, rows *csv.Writer) (string, error) {
+	win := ctx.Win
+	test := step.Test
+	ctx.Brightness = core.Clamp(*brightness, 0, 1)
+	for _, k := range sortedKeys(defaults) {
+		core.SetOption(ctx, test, k, defaults[k]) // some defaults, e.g. a count of pins, can't be set back
+	}
+	for _, k := range sortedKeys(step.Options) {
+		if err := core.SetOption(ctx, test, k, step.Options[k]); err != nil {
+			return "", err
+		}
+	}
+
+	var img *image.RGBA
+	n := max(*frames, 1)
+	for f := 1; f <= n; f++ {
+		win.Clear(colornames.Black)
+		test.Run(ctx)
+		if f == n {
+			img = screenshot.Grab(win)
+		}
+		win.Update()
+		ctx.Frames.Tick(time.Now())
+	}
+
+	// A window manager or HiDPI scaling can refuse the requested size.
+	if size := img.Bounds().Size(); size.X != res.Width || size.Y != res.Height {
+		return "", fmt.Errorf("window is %dx%d, not %dx%d", size.X, size.Y, res.Width, res.Height)
+	}
+
+	// The file name carries only the options that were asked for; the
+	// index and the text chunks have all of them.
+	meta := screenshot.Meta{
+		Test:       core.TestID(test),
+		Name:       test.Name(),
+		Options:    step.Options,
+		Brightness: ctx.Brightness,
+		Software:   version.AppFullName,
+		Time:       time.Now(),
+	}
+	name := fmt.Sprintf("%02d_%s.png", i+1, screenshot.Slug(meta))
+	meta.Options = core.OptionValues(test)
+	path := filepath.Join(dir, name)
+	if err := screenshot.Write(path, img, meta); err != nil {
+		return "", err
+	}
+
+	var opts []string
+	for _, k := range sortedKeys(meta.Options) {
+		opts = append(opts, k+"="+meta.Options[k])
+	}
+	rows.Write([]string{
+		filepath.ToSlash(filepath.Join(res.Label, name)),
+		fmt.Sprintf("%dx%d", res.Width, res.Height),
+		meta.Test,
+		meta.Name,
+		strings.Join(opts, ", "),
+		strconv.FormatFloat(meta.Brightness, 'f', 2, 64),
+	})
+	return path, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func main() {
+	flag.Parse()
+
+	// Without a display GLFW doesn't start, and pixelgl panics on the main
+	// thread when the window is opened rather than returning an error.
+	code := 0
+	defer func() {
+		if windowOpen.Load() {
+			return
+		}
+		if r := recover(); r != nil {
+			fmt.Fprintln(os.Stderr, r)
+			fmt.Fprintln(os.Stderr, displayHint)
+			os.Exit(1)
+		}
+	}()
+	pixelgl.Run(func() { code = run() })
+	os.Exit(code)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseResolutions(t *testing.T) {
+	cases := []struct {
+		list string
+		want []resolution
+		err  bool
+	}{
+		{list: "1080p", want: []resolution{{"1080p", 1920, 1080}}},
+		{list: "720p, 4K,8k", want: []resolution{{"720p", 1280, 720}, {"4k", 3840, 2160}, {"8k", 7680, 4320}}},
+		{list: "1440p,1024x768", want: []resolution{{"1440p", 2560, 1440}, {"1024x768", 1024, 768}}},
+		{list: "800X600", want: []resolution{{"800x600", 800, 600}}},
+		{list: "", err: true},
+		{list: "1080p,", err: true},
+		{list: "5k", err: true},
+		{list: "1024x", err: true},
+		{list: "0x768", err: true},
+		{list: "-1x768", err: true},
+		{list: "1024x768x2", err: true},
+	}
+	for _, c := range cases {
+		got, err := parseResolutions(c.list)
+		if c.err {
+			if err == nil {
+				t.Errorf("parseResolutions(%q) = %v, want an error", c.list, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("parseResolutions(%q) = %v, %v; want %v", c.list, got, err, c.want)
+		}
+	}
+}
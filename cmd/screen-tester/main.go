@@ -1,7 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/faiface/pixel"
@@ -10,12 +13,43 @@ import (
 	"golang.org/x/image/colornames"
 
 	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/defects"
+	"github.com/keshon/screen-tester/internal/frametime"
 	"github.com/keshon/screen-tester/internal/input"
+	"github.com/keshon/screen-tester/internal/inspection"
+	"github.com/keshon/screen-tester/internal/playlist"
+	"github.com/keshon/screen-tester/internal/remote"
+	"github.com/keshon/screen-tester/internal/report"
+	"github.com/keshon/screen-tester/internal/screenshot"
+	"github.com/keshon/screen-tester/internal/soak"
 	_ "github.com/keshon/screen-tester/internal/tests" // auto-register tests
 	"github.com/keshon/screen-tester/internal/ui"
 	"github.com/keshon/screen-tester/internal/version"
 )
 
+var (
+	imagePath    = flag.String("images", "images", "directory or file with PNG/JPEG images for the User Images test")
+	playlistSpec = flag.String("playlist", "", `slideshow steps, e.g. "checkerboard@5s size=4; zone-plate@20s mode=circular,contrast=50"; starts playing at launch`)
+	loop         = flag.Bool("loop", true, "restart the slideshow after the last step")
+	slideTime    = flag.Duration("slide", playlist.DefaultDuration, "time per test when no -playlist is given")
+	scriptPath   = flag.String("script", "", "JSON test sequence to run at launch, with per-step options and operator instructions")
+	shuffle      = flag.Bool("shuffle", false, "play the slideshow in random order, reshuffled on every pass")
+	soakMode     = flag.Bool("soak", false, "unattended burn-in: loop the slideshow, recover from test panics and log a heartbeat")
+	soakTests    = flag.String("soak-tests", "", "comma-separated test IDs to cycle in soak mode (default: all tests)")
+	soakLog      = flag.String("soak-log", "soak.log", "file the soak log is appended to")
+	heartbeat    = flag.Duration("heartbeat", time.Minute, "interval between soak heartbeat log entries")
+	inspect      = flag.Bool("inspect", false, "production-line pass/fail inspection of the -playlist or -script plan (default: all tests), menu locked")
+	resultsPath  = flag.String("results", "inspection.csv", "CSV file inspection verdicts are appended to")
+	minView      = flag.Duration("min-view", 3*time.Second, "minimum viewing time per test before a verdict when inspecting without a plan")
+	httpAddr     = flag.String("http", "", "serve the control API on this loopback address, e.g. 127.0.0.1:8765")
+	stdinCmds    = flag.Bool("stdin", false, "read line commands (test, set, brightness, next, quit, ...) from stdin and answer OK/ERR on stdout")
+	socketPath   = flag.String("socket", "", "serve line commands on this Unix domain socket")
+	reportDir    = flag.String("reports", "reports", "directory session reports (JSON, CSV, HTML) are written to")
+	reportOnExit = flag.Bool("report-on-exit", true, "write a session report on exit if verdicts, defect marks or notes were recorded, or after a soak run")
+	shotDir      = flag.String("screenshots", "screenshots", "directory screenshots (F9, Shift+F9 with overlays) are saved to")
+	shotAndExit  = flag.Bool("screenshot", false, "save a screenshot of the first step of the -playlist or -script, then exit")
+)
+
 func run() {
 	monitor := pixelgl.PrimaryMonitor()
 	width, height := monitor.Size()
@@ -39,8 +73,24 @@ func run() {
 		ScreenHeight: int(height),
 		ShowInfo:     true,
 		Brightness:   1.0,
+		ImagePath:    *imagePath,
+		Frames:       frametime.NewRecorder(240, monitor.RefreshRate()),
 	}
 
+	physW, physH := monitor.PhysicalSize()
+	bitsR, bitsG, bitsB := monitor.BitDepth()
+	session := report.NewRecorder(version.AppFullName, report.Monitor{
+		Name:        monitor.Name(),
+		Width:       int(width),
+		Height:      int(height),
+		RefreshRate: monitor.RefreshRate(),
+		PhysicalW:   physW,
+		PhysicalH:   physH,
+		BitsRed:     bitsR,
+		BitsGreen:   bitsG,
+		BitsBlue:    bitsB,
+	}, time.Now())
+
 	tests := core.AllTests()
 
 	menuButtons := make([]ui.Button, 0, len(tests)+1)
@@ -60,14 +110,178 @@ func run() {
 
 	showMenu := true
 	currentTest := tests[0]
-	testControls := &input.TestInput{}
+	testControls := &input.TestInput{Annotator: defects.NewAnnotator()}
+	notePrompt := &input.TextPrompt{}
+
+	exportReport := func() ([]string, error) {
+		return report.Write(*reportDir, session.Snapshot(ctx, testControls.Annotator.Marks, time.Now()))
+	}
+	camera := screenshot.NewCamera(*shotDir, version.AppFullName)
+
+	// notice is a short-lived message in the info overlay.
+	var notice string
+	var noticeUntil time.Time
+	setNotice := func(msg string) {
+		notice = msg
+		noticeUntil = time.Now().Add(5 * time.Second)
+	}
+	quit := false
+
+	list := playlist.Default(tests, *slideTime)
+	list.Loop = *loop
+	switch {
+	case *playlistSpec != "" && *scriptPath != "":
+		fmt.Fprintln(os.Stderr, "use either -playlist or -script")
+		os.Exit(2)
+	case *soakTests != "" && (*playlistSpec != "" || *scriptPath != ""):
+		fmt.Fprintln(os.Stderr, "-soak-tests picks the tests itself; drop -playlist or -script")
+		os.Exit(2)
+	case *playlistSpec != "":
+		list, err = playlist.Parse(*playlistSpec, *loop)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "playlist:", err)
+			os.Exit(2)
+		}
+	case *scriptPath != "":
+		list, err = playlist.LoadScript(*scriptPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "script:", err)
+			os.Exit(2)
+		}
+	case *soakTests != "":
+		var selected []core.ScreenTest
+		for _, ref := range strings.Split(*soakTests, ",") {
+			t, ok := core.FindTest(strings.TrimSpace(ref))
+			if !ok {
+				fmt.Fprintf(os.Stderr, "soak-tests: unknown test %q\n", ref)
+				os.Exit(2)
+			}
+			selected = append(selected, t)
+		}
+		list = playlist.Default(selected, *slideTime)
+	}
+	list.Shuffle = *shuffle
+	testControls.Slideshow = playlist.NewPlayer(list)
+
+	if *soakMode {
+		list.Loop = true
+	}
+
+	var inspector *inspection.Inspector
+	if *inspect {
+		if *soakMode {
+			fmt.Fprintln(os.Stderr, "use either -inspect or -soak")
+			os.Exit(2)
+		}
+		plan := list
+		if *playlistSpec == "" && *scriptPath == "" {
+			plan = playlist.Default(tests, *minView)
+		}
+		inspector = inspection.New(plan, *resultsPath)
+		inspector.Report = session
+	} else if *playlistSpec != "" || *scriptPath != "" || *soakMode {
+		testControls.Slideshow.Start(ctx, time.Now())
+		showMenu = false
+	}
+
+	if *shotAndExit {
+		if showMenu || inspector != nil {
+			fmt.Fprintln(os.Stderr, "-screenshot needs -playlist or -script to choose the test")
+			os.Exit(2)
+		}
+		camera.Request(false, func(path string, err error) {
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "screenshot:", err)
+			} else {
+				fmt.Println(path)
+			}
+			quit = true
+		})
+	}
+
+	remoteQueue := remote.NewQueue()
+	remoteTarget := &remote.Target{
+		Ctx:      ctx,
+		Tests:    tests,
+		Controls: testControls,
+		ShowMenu: &showMenu,
+		AddNote: func(text string) {
+			session.AddNote(tests[testControls.Current], text, time.Now())
+		},
+		ExportReport:   exportReport,
+		SaveScreenshot: camera.Request,
+	}
+	if inspector != nil && (*httpAddr != "" || *stdinCmds || *socketPath != "") {
+		fmt.Fprintln(os.Stderr, "remote control is not available while inspecting")
+		os.Exit(2)
+	}
+	if *httpAddr != "" {
+		if err := remote.ListenHTTP(*httpAddr, remoteQueue); err != nil {
+			fmt.Fprintln(os.Stderr, "http:", err)
+			os.Exit(2)
+		}
+	}
+	if *socketPath != "" {
+		if err := remote.ListenUnix(*socketPath, remoteQueue); err != nil {
+			fmt.Fprintln(os.Stderr, "socket:", err)
+			os.Exit(2)
+		}
+		defer os.Remove(*socketPath)
+	}
+
+	// The log is opened last, so a run that fails validation leaves no entry.
+	var soakSession *soak.Session
+	if *soakMode {
+		soakSession, err = soak.Start(*soakLog, *heartbeat, time.Now())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "soak:", err)
+			os.Exit(2)
+		}
+		defer soakSession.Close()
+	}
+	if *stdinCmds {
+		go remote.ServeLines(os.Stdin, os.Stdout, remoteQueue)
+	}
+
+	// In soak mode a panic in a test, whether drawing, applying options or
+	// reporting them, is logged and the test skipped so the run can go on
+	// overnight. Skipping applies the next step's options, which is guarded
+	// once more but not retried.
+	guard := func(step func(*core.WindowContext)) {
+		if soakSession == nil {
+			step(ctx)
+			return
+		}
+		show := testControls.Slideshow
+		core.WithPanicHandler(func(r interface{}, stack []byte) {
+			failed := tests[testControls.Current]
+			if show.Active() {
+				failed = show.Current()
+			}
+			soakSession.Recovered(failed, r, stack)
+			if show.Active() {
+				core.WithPanicHandler(func(r interface{}, stack []byte) {
+					soakSession.Recovered(show.Current(), r, stack)
+				})(func(ctx *core.WindowContext) { show.Next(ctx, time.Now()) })(ctx)
+			}
+		})(step)(ctx)
+	}
 
 	cursor := imdraw.New(nil)
 
 	for !win.Closed() {
 		ctx.Win.Clear(colornames.Black)
+		guard(func(*core.WindowContext) { remoteQueue.Drain(remoteTarget) })
+		if remoteTarget.Quit {
+			remoteTarget.AwaitQuitReply()
+			break
+		}
 
-		if showMenu {
+		if inspector != nil {
+			if inspector.Frame(ctx) {
+				break
+			}
+		} else if showMenu {
 			ui.DrawPixelTitle(ctx.Win, "SCREEN TESTER", ctx.Win.Bounds().W(), ctx.Win.Bounds().H(), time.Now())
 			ui.DrawTitle(ctx.Win,
 				fmt.Sprintf("%s - %s\nMade by %s (%s)",
@@ -100,26 +314,114 @@ func run() {
 			cursor.Circle(6, 2)
 			cursor.Draw(win)
 
+		} else if notePrompt.Active {
+			// The test stays on screen while typing, but doesn't get the keys.
+			if notePrompt.Update(win) && strings.TrimSpace(notePrompt.Text) != "" {
+				session.AddNote(currentTest, strings.TrimSpace(notePrompt.Text), time.Now())
+			}
+			ctx.InputBlocked = true
+			guard(currentTest.Run)
+			ctx.InputBlocked = false
+			ui.DrawInstruction(ctx, "Note on "+currentTest.Name()+": "+notePrompt.Text+"_ (Enter: save, ESC: cancel)")
+
 		} else {
+			guard(func(ctx *core.WindowContext) { testControls.HandleTestInput(ctx, tests) })
 			currentTest = tests[testControls.Current]
-			testControls.HandleTestInput(ctx, tests)
 
-			if ctx.Win.JustPressed(pixelgl.KeyEscape) {
-				showMenu = true
-				continue
+			if !ctx.TextEntry {
+				if ctx.Win.JustPressed(pixelgl.KeyEscape) {
+					testControls.Slideshow.Stop(ctx)
+					showMenu = true
+					continue
+				}
+				if ctx.Win.JustPressed(pixelgl.KeyF8) {
+					notePrompt.Open()
+				}
+				if ctx.Win.JustPressed(pixelgl.KeyF7) {
+					if paths, err := exportReport(); err != nil {
+						setNotice("Report failed: " + err.Error())
+					} else {
+						setNotice("Report saved: " + paths[len(paths)-1])
+					}
+				}
+				if ctx.Win.JustPressed(pixelgl.KeyF9) {
+					overlays := ctx.Win.Pressed(pixelgl.KeyLeftShift) || ctx.Win.Pressed(pixelgl.KeyRightShift)
+					camera.Request(overlays, func(path string, err error) {
+						if err != nil {
+							setNotice("Screenshot failed: " + err.Error())
+						} else {
+							setNotice("Screenshot saved: " + path)
+						}
+					})
+				}
 			}
+			// The test sets it again if it's still taking text.
+			ctx.TextEntry = false
 
-			currentTest.Run(ctx)
+			guard(currentTest.Run)
+			if soakSession != nil && testControls.Slideshow.Active() {
+				soakSession.Update(ctx, currentTest, testControls.Slideshow.Cycles(), time.Now())
+			}
+			guard(func(ctx *core.WindowContext) { camera.Capture(ctx, currentTest, false) })
 
 			if ctx.ShowInfo {
-				ui.DrawInfo(ctx, currentTest, currentTest.Options(), ctx.Brightness)
+				var status []string
+				if testControls.Slideshow.Active() {
+					status = append(status, testControls.Slideshow.Status(time.Now()))
+					if soakSession != nil {
+						status = append(status, soakSession.Status(testControls.Slideshow.Cycles(), time.Now()))
+					}
+				}
+				if testControls.Annotator.Active || len(testControls.Annotator.Marks) > 0 {
+					status = append(status, testControls.Annotator.Status())
+				}
+				if time.Now().Before(noticeUntil) {
+					status = append(status, notice)
+				}
+				guard(func(ctx *core.WindowContext) {
+					ui.DrawInfo(ctx, currentTest, currentTest.Options(), ctx.Brightness, status...)
+				})
+			}
+			if ctx.ShowTiming {
+				ui.DrawFrameTiming(ctx)
+			}
+			if testControls.Slideshow.Active() {
+				ui.DrawInstruction(ctx, testControls.Slideshow.Instruction())
 			}
+			testControls.Annotator.Draw(ctx)
+			guard(func(ctx *core.WindowContext) { camera.Capture(ctx, currentTest, true) })
+		}
+
+		var onScreen core.ScreenTest
+		if inspector != nil {
+			onScreen = inspector.Current()
+		} else if !showMenu {
+			onScreen = tests[testControls.Current]
+		}
+		guard(func(ctx *core.WindowContext) { session.Track(ctx, onScreen, time.Now()) })
+		if quit {
+			break
 		}
 
 		win.Update()
+		ctx.Frames.Tick(time.Now())
+	}
+
+	session.Track(ctx, nil, time.Now())
+	// Only sessions with findings get a report; just looking at tests
+	// doesn't leave files behind.
+	recorded := len(session.Verdicts) > 0 || len(session.Notes) > 0 || len(testControls.Annotator.Marks) > 0 || soakSession != nil
+	if *reportOnExit && !*shotAndExit && recorded {
+		paths, err := exportReport()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "report:", err)
+		} else {
+			fmt.Fprintln(os.Stderr, "report written to", strings.Join(paths, ", "))
+		}
 	}
 }
 
 func main() {
+	flag.Parse()
 	pixelgl.Run(run)
 }
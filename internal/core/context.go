@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/faiface/pixel/pixelgl"
+
+	"github.com/keshon/screen-tester/internal/frametime"
 )
 
 type WindowContext struct {
@@ -13,4 +15,14 @@ type WindowContext struct {
 	ShowInfo        bool
 	ScreenWidth     int
 	ScreenHeight    int
+	ImagePath       string
+	Frames          *frametime.Recorder
+	ShowTiming      bool
+	// TextEntry is set by a test while it takes typed text, so the keys
+	// don't also switch tests, toggle overlays or leave to the menu. The
+	// main loop clears it before each frame's test runs.
+	TextEntry bool
+	// InputBlocked is set while the keys and mouse belong to something
+	// drawn over the test, so the test doesn't act on them too.
+	InputBlocked bool
 }
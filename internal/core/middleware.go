@@ -1,6 +1,11 @@
 package core
 
-import "fmt"
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/faiface/pixel"
+)
 
 type Middleware func(func(*WindowContext)) func(*WindowContext)
 
@@ -26,12 +31,27 @@ func WithWindowGuard(next func(*WindowContext)) func(*WindowContext) {
 }
 
 func WithRecover(next func(*WindowContext)) func(*WindowContext) {
-	return func(ctx *WindowContext) {
-		defer func() {
-			if r := recover(); r != nil {
-				fmt.Printf("[panic recovered] %v\n", r)
-			}
-		}()
-		next(ctx)
+	return WithPanicHandler(func(r interface{}, stack []byte) {
+		fmt.Printf("[panic recovered] %v\n", r)
+	})(next)
+}
+
+// WithPanicHandler recovers from a panic in next and passes the value and
+// stack trace to handle, so the caller can log it and move on. Drawing state
+// the test may have left changed is reset first.
+func WithPanicHandler(handle func(r interface{}, stack []byte)) Middleware {
+	return func(next func(*WindowContext)) func(*WindowContext) {
+		return func(ctx *WindowContext) {
+			defer func() {
+				if r := recover(); r != nil {
+					if ctx.Win != nil {
+						ctx.Win.SetComposeMethod(pixel.ComposeOver)
+						ctx.Win.SetColorMask(pixel.Alpha(1))
+					}
+					handle(r, debug.Stack())
+				}
+			}()
+			next(ctx)
+		}
 	}
 }
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Configurable is implemented by tests whose options can be set by key, so
+// playlists, scripts and remote commands can do what the keyboard does.
+// Settings lists every key SetOption accepts with its current value in a
+// form SetOption takes back. Unlike Options().Extra, which only shows what
+// matters in the current mode, it doesn't change with the mode.
+type Configurable interface {
+	SetOption(key, value string) error
+	Settings() map[string]string
+}
+
+var ErrUnknownOption = errors.New("unknown option")
+
+// SetOption sets an option of t, or the shared brightness when key is "brightness".
+func SetOption(ctx *WindowContext, t ScreenTest, key, value string) error {
+	if key == "brightness" {
+		b, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("brightness: %w", err)
+		}
+		ctx.Brightness = Clamp(b, 0, 1)
+		return nil
+	}
+	if err := CheckOption(t, key); err != nil {
+		return err
+	}
+	if err := t.(Configurable).SetOption(key, value); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// ValidateOption reports whether SetOption would accept value for key on
+// t. It tries the value and then puts every setting back, since some
+// options change others.
+func ValidateOption(t ScreenTest, key, value string) error {
+	if key == "brightness" {
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("brightness: %w", err)
+		}
+		return nil
+	}
+	if err := CheckOption(t, key); err != nil {
+		return err
+	}
+	c := t.(Configurable)
+	before := c.Settings()
+	err := c.SetOption(key, value)
+	keys := make([]string, 0, len(before))
+	for k := range before {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		c.SetOption(k, before[k])
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// CheckOption reports whether key can be set on t without changing anything.
+func CheckOption(t ScreenTest, key string) error {
+	if key == "brightness" {
+		return nil
+	}
+	if c, ok := t.(Configurable); ok {
+		if _, ok := c.Settings()[key]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %q for test %q", ErrUnknownOption, key, TestID(t))
+}
+
+// Settings returns the settable options of t with their values, or nil if
+// it has none.
+func Settings(t ScreenTest) map[string]string {
+	if c, ok := t.(Configurable); ok {
+		return c.Settings()
+	}
+	return nil
+}
+
+// OptionValues formats the options of t as strings that SetOption accepts back.
+func OptionValues(t ScreenTest) map[string]string {
+	extra := t.Options().Extra
+	values := make(map[string]string, len(extra))
+	for k, v := range extra {
+		values[k] = fmt.Sprint(v)
+	}
+	return values
+}
+
+// ParseBool accepts on/off and yes/no besides the strconv spellings.
+func ParseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "on", "yes":
+		return true, nil
+	case "off", "no":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// ParseDurationMs accepts Go durations ("250ms") or a plain number of milliseconds.
+func ParseDurationMs(value string) (time.Duration, error) {
+	if n, err := strconv.Atoi(value); err == nil {
+		return time.Duration(n) * time.Millisecond, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// ParsePercent accepts "25" or "25%".
+func ParsePercent(value string) (int, error) {
+	return strconv.Atoi(strings.TrimSuffix(value, "%"))
+}
+
+// Choose returns the index of value in choices, ignoring case.
+func Choose(value string, choices []string) (int, error) {
+	for i, c := range choices {
+		if strings.EqualFold(c, value) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%q is not one of %s", value, strings.Join(choices, ", "))
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"sort"
+	"strings"
 )
 
 var registry = map[string]ScreenTest{}
@@ -15,6 +16,36 @@ func GetTest(name string) (ScreenTest, bool) {
 	return t, ok
 }
 
+// TestID returns the stable identifier used by playlists and remote
+// commands: the test name in lower case with spaces replaced by dashes.
+func TestID(t ScreenTest) string {
+	return strings.ReplaceAll(strings.ToLower(t.Name()), " ", "-")
+}
+
+// FindTest looks a test up by ID or by name, ignoring case. A ref that is
+// neither may also be the leading or trailing words of exactly one ID, so
+// "checkerboard" finds small-checkerboard but "gradient" finds nothing.
+func FindTest(ref string) (ScreenTest, bool) {
+	id := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(ref)), " ", "-")
+	if id == "" {
+		return nil, false
+	}
+	var partial []ScreenTest
+	for _, t := range registry {
+		tid := TestID(t)
+		if tid == id {
+			return t, true
+		}
+		if strings.HasPrefix(tid, id+"-") || strings.HasSuffix(tid, "-"+id) {
+			partial = append(partial, t)
+		}
+	}
+	if len(partial) == 1 {
+		return partial[0], true
+	}
+	return nil, false
+}
+
 func AllTests() []ScreenTest {
 	list := make([]ScreenTest, 0, len(registry))
 	for _, t := range registry {
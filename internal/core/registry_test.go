@@ -0,0 +1,38 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/keshon/screen-tester/internal/core"
+	_ "github.com/keshon/screen-tester/internal/tests" // auto-register tests
+)
+
+func TestFindTest(t *testing.T) {
+	cases := []struct {
+		ref  string
+		want string // test ID, or "" if not found
+	}{
+		{"small-checkerboard", "small-checkerboard"},
+		{"Small Checkerboard", "small-checkerboard"},
+		{"SMALL-CHECKERBOARD", "small-checkerboard"},
+		{"checkerboard", "small-checkerboard"},
+		{"pursuit-sync", "pursuit-sync-track"},
+		{"sync-track", "pursuit-sync-track"},
+		{"white", "white"},
+		{"gradient", ""}, // horizontal and vertical
+		{"checker", ""},  // not a whole word
+		{"sync", ""},     // neither leading nor trailing
+		{"", ""},
+		{"no-such-test", ""},
+	}
+	for _, c := range cases {
+		found, ok := core.FindTest(c.ref)
+		got := ""
+		if ok {
+			got = core.TestID(found)
+		}
+		if got != c.want {
+			t.Errorf("FindTest(%q) = %q, want %q", c.ref, got, c.want)
+		}
+	}
+}
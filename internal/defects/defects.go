@@ -0,0 +1,216 @@
+package defects
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/ui"
+)
+
+// Kinds are the defect types, selected with keys 1 to 6.
+var Kinds = []struct {
+	Name  string
+	Color color.RGBA
+}{
+	{"dead", colornames.Red},
+	{"stuck-on", colornames.Yellow},
+	{"stuck color", colornames.Magenta},
+	{"bright dot", colornames.Cyan},
+	{"dust", colornames.Orange},
+	{"mura", colornames.Lime},
+}
+
+// Mark is a defect at panel coordinates: x from the left edge and y from
+// the top edge, in pixels.
+type Mark struct {
+	X    int       `json:"x"`
+	Y    int       `json:"y"`
+	Kind string    `json:"kind"`
+	Test string    `json:"test"` // ID of the test it was marked on
+	Time time.Time `json:"time"`
+}
+
+// Annotator records marks over whatever test is showing. The marks outlive
+// test switches; F4 toggles marking and F6 the overlay.
+type Annotator struct {
+	Marks   []Mark
+	Active  bool // marking mode: keys and mouse work the crosshair, not the test
+	Visible bool
+	kind    int
+	x, y    int // crosshair in panel coordinates
+}
+
+func NewAnnotator() *Annotator {
+	return &Annotator{Visible: true}
+}
+
+// HandleKeys processes the annotation keys; test is the one being shown.
+func (a *Annotator) HandleKeys(ctx *core.WindowContext, test core.ScreenTest) {
+	win := ctx.Win
+	width, height := int(win.Bounds().W()), int(win.Bounds().H())
+
+	if win.JustPressed(pixelgl.KeyF4) {
+		a.Active = !a.Active
+		if a.Active {
+			a.Visible = true
+			a.x, a.y = width/2, height/2
+		}
+	}
+	if win.JustPressed(pixelgl.KeyF6) {
+		a.Visible = !a.Visible
+	}
+	if !a.Active {
+		return
+	}
+
+	step := 1
+	if win.Pressed(pixelgl.KeyLeftControl) || win.Pressed(pixelgl.KeyRightControl) {
+		step = 10
+	}
+	if pressed(win, pixelgl.KeyLeft) {
+		a.x -= step
+	}
+	if pressed(win, pixelgl.KeyRight) {
+		a.x += step
+	}
+	if pressed(win, pixelgl.KeyUp) {
+		a.y -= step
+	}
+	if pressed(win, pixelgl.KeyDown) {
+		a.y += step
+	}
+	if win.MousePosition() != win.MousePreviousPosition() {
+		a.x, a.y = toPanel(win.MousePosition(), height)
+	}
+	a.x = clamp(a.x, 0, width-1)
+	a.y = clamp(a.y, 0, height-1)
+
+	for i := range Kinds {
+		if win.JustPressed(pixelgl.Key1 + pixelgl.Button(i)) {
+			a.kind = i
+		}
+	}
+
+	if win.JustPressed(pixelgl.MouseButtonLeft) || win.JustPressed(pixelgl.KeySpace) || win.JustPressed(pixelgl.KeyEnter) {
+		a.Marks = append(a.Marks, Mark{
+			X:    a.x,
+			Y:    a.y,
+			Kind: Kinds[a.kind].Name,
+			Test: core.TestID(test),
+			Time: time.Now(),
+		})
+	}
+	if win.JustPressed(pixelgl.MouseButtonRight) || win.JustPressed(pixelgl.KeyBackspace) {
+		a.removeNear(a.x, a.y, 10)
+	}
+}
+
+// Status is the line shown in the info overlay.
+func (a *Annotator) Status() string {
+	if !a.Active {
+		return fmt.Sprintf("Defects: %d marked (F4: mark)", len(a.Marks))
+	}
+	return fmt.Sprintf("Marking %s at %d,%d - %d marked", Kinds[a.kind].Name, a.x, a.y, len(a.Marks))
+}
+
+// Draw draws the marks and, while marking, the crosshair.
+func (a *Annotator) Draw(ctx *core.WindowContext) {
+	if !a.Visible && !a.Active {
+		return
+	}
+	win := ctx.Win
+	height := win.Bounds().H()
+
+	imd := imdraw.New(nil)
+	labels := text.New(pixel.ZV, ui.Atlas)
+
+	for _, m := range a.Marks {
+		c := KindColor(m.Kind)
+		center := toWindow(m.X, m.Y, height)
+		imd.Color = c
+		imd.Push(center)
+		imd.Circle(8, 1)
+		labels.Color = c
+		labels.Dot = center.Add(pixel.V(11, 3))
+		fmt.Fprintf(labels, "%s %d,%d", m.Kind, m.X, m.Y)
+	}
+
+	if a.Active {
+		// One pixel wide lines that stop short of the target pixel, so the
+		// pixel itself stays visible.
+		const gap = 4
+		c := toWindow(a.x, a.y, height)
+		imd.Color = Kinds[a.kind].Color
+		for _, seg := range [][2]pixel.Vec{
+			{pixel.V(0, c.Y), pixel.V(c.X-gap, c.Y)},
+			{pixel.V(c.X+gap, c.Y), pixel.V(win.Bounds().W(), c.Y)},
+			{pixel.V(c.X, 0), pixel.V(c.X, c.Y-gap)},
+			{pixel.V(c.X, c.Y+gap), pixel.V(c.X, height)},
+		} {
+			imd.Push(seg[0], seg[1])
+			imd.Line(1)
+		}
+		labels.Color = Kinds[a.kind].Color
+		labels.Dot = c.Add(pixel.V(gap+4, -gap-12))
+		fmt.Fprintf(labels, "%d,%d %s (1-6: type, Click/Space: mark, Right click/Backspace: remove)", a.x, a.y, Kinds[a.kind].Name)
+	}
+
+	imd.Draw(win)
+	labels.Draw(win, pixel.IM)
+}
+
+func (a *Annotator) removeNear(x, y, radius int) {
+	best, bestDist := -1, math.MaxFloat64
+	for i, m := range a.Marks {
+		d := math.Hypot(float64(m.X-x), float64(m.Y-y))
+		if d <= float64(radius) && d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best >= 0 {
+		a.Marks = append(a.Marks[:best], a.Marks[best+1:]...)
+	}
+}
+
+// KindColor returns the overlay color for a defect kind.
+func KindColor(kind string) color.RGBA {
+	for _, k := range Kinds {
+		if k.Name == kind {
+			return k.Color
+		}
+	}
+	return colornames.White
+}
+
+// toPanel converts a window position to panel coordinates with y from the top.
+func toPanel(v pixel.Vec, height int) (int, int) {
+	return int(math.Floor(v.X)), height - 1 - int(math.Floor(v.Y))
+}
+
+// toWindow returns the window position of the centre of a panel pixel.
+func toWindow(x, y int, height float64) pixel.Vec {
+	return pixel.V(float64(x)+0.5, height-float64(y)-0.5)
+}
+
+func pressed(win *pixelgl.Window, key pixelgl.Button) bool {
+	return win.JustPressed(key) || win.Repeated(key)
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -0,0 +1,84 @@
+package frametime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func record(r *Recorder, intervals ...time.Duration) {
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	r.Tick(now)
+	for _, d := range intervals {
+		now = now.Add(d)
+		r.Tick(now)
+	}
+}
+
+func TestStats(t *testing.T) {
+	const ms = time.Millisecond
+	r := NewRecorder(8, 100) // 10 ms period
+	if s := r.Stats(); s != (Stats{}) {
+		t.Errorf("empty recorder: %+v", s)
+	}
+
+	// Three on-time frames and one that missed a refresh.
+	record(r, 10*ms, 10*ms, 10*ms, 20*ms)
+	s := r.Stats()
+	want := Stats{
+		Samples:    4,
+		MeasuredHz: 80,
+		Mean:       12500 * time.Microsecond,
+		Jitter:     time.Duration(4330127), // sqrt(3*2.5² + 7.5²)/2 ms
+		Min:        10 * ms,
+		Max:        20 * ms,
+		Dropped:    1,
+	}
+	want.Histogram[4] = 3
+	want.Histogram[8] = 1
+	if s != want {
+		t.Errorf("Stats() =\n %+v\nwant\n %+v", s, want)
+	}
+
+	// Intervals far beyond a period land in the last bucket and count
+	// every refresh they missed.
+	r.Reset()
+	record(r, time.Second)
+	if s := r.Stats(); s.Histogram[HistogramBins-1] != 1 || s.Dropped != 99 {
+		t.Errorf("long frame: %+v", s)
+	}
+}
+
+func TestIntervalsWrap(t *testing.T) {
+	const ms = time.Millisecond
+	r := NewRecorder(3, 60)
+	record(r, 1*ms, 2*ms, 3*ms, 4*ms, 5*ms)
+	if got, want := r.Intervals(), []time.Duration{3 * ms, 4 * ms, 5 * ms}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Intervals() = %v, want %v", got, want)
+	}
+
+	r.Reset()
+	if n := len(r.Intervals()); n != 0 {
+		t.Errorf("after Reset: %d intervals", n)
+	}
+	// The first tick after a reset only sets the start.
+	record(r, 7*ms)
+	if got := r.Intervals(); len(got) != 1 || got[0] != 7*ms {
+		t.Errorf("after Reset and two ticks: %v", got)
+	}
+}
+
+func TestPeriod(t *testing.T) {
+	if p := NewRecorder(4, 0).Period(); p != 0 {
+		t.Errorf("unknown rate: period %v", p)
+	}
+	if p := NewRecorder(4, 50).Period(); p != 20*time.Millisecond {
+		t.Errorf("50 Hz: period %v", p)
+	}
+	// Without a nominal rate there is no histogram or drop count.
+	r := NewRecorder(4, 0)
+	record(r, 5*time.Millisecond, 50*time.Millisecond)
+	if s := r.Stats(); s.Dropped != 0 || s.Histogram != [HistogramBins]int{} || s.Samples != 2 {
+		t.Errorf("no nominal rate: %+v", s)
+	}
+}
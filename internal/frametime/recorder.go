@@ -0,0 +1,125 @@
+package frametime
+
+import (
+	"math"
+	"time"
+)
+
+// HistogramBins is the number of histogram buckets. Each covers a quarter of
+// the nominal frame period centred on a multiple of it, so bucket 4 holds
+// intervals of one period; the last bucket collects everything longer.
+const HistogramBins = 12
+
+// Recorder keeps a ring buffer of intervals between presented frames.
+type Recorder struct {
+	Nominal float64 // expected refresh rate in Hz
+
+	intervals []time.Duration
+	next      int
+	count     int
+	last      time.Time
+}
+
+// Stats summarises the recorded intervals.
+type Stats struct {
+	Samples    int
+	MeasuredHz float64
+	Mean       time.Duration
+	Jitter     time.Duration // standard deviation of the intervals
+	Min        time.Duration
+	Max        time.Duration
+	Dropped    int // refreshes missed by intervals longer than 1.5 nominal periods
+	Histogram  [HistogramBins]int
+}
+
+func NewRecorder(history int, nominal float64) *Recorder {
+	return &Recorder{
+		Nominal:   nominal,
+		intervals: make([]time.Duration, history),
+	}
+}
+
+// Tick records the time a frame was presented.
+func (r *Recorder) Tick(now time.Time) {
+	if !r.last.IsZero() {
+		r.intervals[r.next] = now.Sub(r.last)
+		r.next = (r.next + 1) % len(r.intervals)
+		if r.count < len(r.intervals) {
+			r.count++
+		}
+	}
+	r.last = now
+}
+
+// Reset drops all samples, e.g. after the vsync mode changed.
+func (r *Recorder) Reset() {
+	r.next = 0
+	r.count = 0
+	r.last = time.Time{}
+}
+
+// Last returns the time of the most recent frame, zero before the first one.
+func (r *Recorder) Last() time.Time {
+	return r.last
+}
+
+// Period returns the nominal frame period.
+func (r *Recorder) Period() time.Duration {
+	if r.Nominal <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / r.Nominal)
+}
+
+// Intervals returns the recorded intervals, oldest first.
+func (r *Recorder) Intervals() []time.Duration {
+	out := make([]time.Duration, 0, r.count)
+	start := (r.next - r.count + len(r.intervals)) % len(r.intervals)
+	for i := 0; i < r.count; i++ {
+		out = append(out, r.intervals[(start+i)%len(r.intervals)])
+	}
+	return out
+}
+
+func (r *Recorder) Stats() Stats {
+	var s Stats
+	intervals := r.Intervals()
+	s.Samples = len(intervals)
+	if s.Samples == 0 {
+		return s
+	}
+
+	period := r.Period()
+	var total time.Duration
+	s.Min = intervals[0]
+	for _, d := range intervals {
+		total += d
+		if d < s.Min {
+			s.Min = d
+		}
+		if d > s.Max {
+			s.Max = d
+		}
+		if period > 0 {
+			if d > period*3/2 {
+				s.Dropped += int(math.Round(float64(d)/float64(period))) - 1
+			}
+			bin := int((4*d + period/2) / period)
+			if bin >= HistogramBins {
+				bin = HistogramBins - 1
+			}
+			s.Histogram[bin]++
+		}
+	}
+
+	s.Mean = total / time.Duration(s.Samples)
+	s.MeasuredHz = float64(s.Samples) / total.Seconds()
+
+	var variance float64
+	for _, d := range intervals {
+		diff := float64(d - s.Mean)
+		variance += diff * diff
+	}
+	s.Jitter = time.Duration(math.Sqrt(variance / float64(s.Samples)))
+	return s
+}
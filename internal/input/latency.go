@@ -0,0 +1,26 @@
+package input
+
+import (
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// LatencyTriggers are the buttons that start a latency measurement. Arrows,
+// Escape and the function keys are left out because they drive navigation.
+var LatencyTriggers = []pixelgl.Button{
+	pixelgl.KeySpace,
+	pixelgl.KeyEnter,
+	pixelgl.KeyKPEnter,
+	pixelgl.MouseButtonLeft,
+	pixelgl.MouseButtonRight,
+	pixelgl.MouseButtonMiddle,
+}
+
+// JustPressedAny reports whether any of the buttons was pressed since the last update.
+func JustPressedAny(win *pixelgl.Window, buttons ...pixelgl.Button) bool {
+	for _, b := range buttons {
+		if win.JustPressed(b) {
+			return true
+		}
+	}
+	return false
+}
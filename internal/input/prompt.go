@@ -0,0 +1,38 @@
+package input
+
+import (
+	"unicode/utf8"
+
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// TextPrompt collects one line of typed text, such as an operator note.
+type TextPrompt struct {
+	Active bool
+	Text   string
+}
+
+func (p *TextPrompt) Open() {
+	p.Active = true
+	p.Text = ""
+}
+
+// Update reads this frame's typing. It reports true once when Enter
+// submits the text; Esc closes the prompt and discards it.
+func (p *TextPrompt) Update(win *pixelgl.Window) bool {
+	p.Text += win.Typed()
+	if (win.JustPressed(pixelgl.KeyBackspace) || win.Repeated(pixelgl.KeyBackspace)) && len(p.Text) > 0 {
+		_, size := utf8.DecodeLastRuneInString(p.Text)
+		p.Text = p.Text[:len(p.Text)-size]
+	}
+	if win.JustPressed(pixelgl.KeyEscape) {
+		p.Active = false
+		p.Text = ""
+		return false
+	}
+	if win.JustPressed(pixelgl.KeyEnter) || win.JustPressed(pixelgl.KeyKPEnter) {
+		p.Active = false
+		return true
+	}
+	return false
+}
@@ -1,20 +1,56 @@
 package input
 
 import (
+	"time"
+
 	"github.com/faiface/pixel/pixelgl"
 	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/defects"
+	"github.com/keshon/screen-tester/internal/playlist"
 )
 
 type TestInput struct {
-	Current int
+	Current   int
+	Slideshow *playlist.Player   // optional; F5 starts and stops it
+	Annotator *defects.Annotator // optional; F4 toggles defect marking
 }
 
 func (ti *TestInput) HandleTestInput(ctx *core.WindowContext, tests []core.ScreenTest) {
 	win := ctx.Win
 
+	// The keys are being typed into the test, not meant for us.
+	if ctx.TextEntry {
+		ti.updateSlideshow(ctx, tests, time.Now())
+		return
+	}
+
 	if win.JustPressed(pixelgl.KeyF1) {
 		ctx.ShowInfo = !ctx.ShowInfo
 	}
+	if win.JustPressed(pixelgl.KeyF2) {
+		ctx.ShowTiming = !ctx.ShowTiming
+	}
+	if win.JustPressed(pixelgl.KeyF3) {
+		win.SetVSync(!win.VSync())
+		if ctx.Frames != nil {
+			ctx.Frames.Reset()
+		}
+	}
+	if ti.Annotator != nil {
+		ti.Annotator.HandleKeys(ctx, tests[ti.Current])
+		// While marking, the arrows, digits and clicks belong to the
+		// crosshair, not to us or the test. The slideshow keeps going.
+		ctx.InputBlocked = ti.Annotator.Active
+		if ti.Annotator.Active {
+			ti.updateSlideshow(ctx, tests, time.Now())
+			return
+		}
+	}
+	if ti.Slideshow != nil {
+		if ti.handleSlideshow(ctx, tests) {
+			return
+		}
+	}
 	if win.JustPressed(pixelgl.KeyRight) {
 		ti.Current = (ti.Current + 1) % len(tests)
 	}
@@ -22,3 +58,52 @@ func (ti *TestInput) HandleTestInput(ctx *core.WindowContext, tests []core.Scree
 		ti.Current = (ti.Current - 1 + len(tests)) % len(tests)
 	}
 }
+
+// handleSlideshow reports whether the slideshow is running and owns the
+// test selection; Left/Right then skip steps, P pauses and Space or Enter
+// ends a step that waits for the operator.
+func (ti *TestInput) handleSlideshow(ctx *core.WindowContext, tests []core.ScreenTest) bool {
+	win := ctx.Win
+	show := ti.Slideshow
+	now := time.Now()
+
+	if win.JustPressed(pixelgl.KeyF5) {
+		if show.Active() {
+			show.Stop(ctx)
+		} else {
+			show.Start(ctx, now)
+		}
+	}
+	if !show.Active() {
+		return false
+	}
+
+	if win.JustPressed(pixelgl.KeyP) {
+		show.TogglePause(now)
+	}
+	if win.JustPressed(pixelgl.KeyRight) {
+		show.Next(ctx, now)
+	} else if show.Waiting() && (win.JustPressed(pixelgl.KeySpace) || win.JustPressed(pixelgl.KeyEnter)) {
+		show.Next(ctx, now)
+	}
+	if win.JustPressed(pixelgl.KeyLeft) {
+		show.Prev(ctx, now)
+	}
+	ti.updateSlideshow(ctx, tests, now)
+	return true
+}
+
+// updateSlideshow advances a running slideshow and selects its test.
+func (ti *TestInput) updateSlideshow(ctx *core.WindowContext, tests []core.ScreenTest, now time.Time) {
+	show := ti.Slideshow
+	if show == nil || !show.Active() {
+		return
+	}
+	show.Update(ctx, now)
+
+	for i, t := range tests {
+		if t == show.Current() {
+			ti.Current = i
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package inspection
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/input"
+	"github.com/keshon/screen-tester/internal/playlist"
+	"github.com/keshon/screen-tester/internal/report"
+	"github.com/keshon/screen-tester/internal/ui"
+)
+
+// verdictKeys are Pass, Fail and the category choices.
+var verdictKeys = []pixelgl.Button{
+	pixelgl.KeyP, pixelgl.KeyX,
+	pixelgl.Key0, pixelgl.Key1, pixelgl.Key2, pixelgl.Key3, pixelgl.Key4,
+	pixelgl.Key5, pixelgl.Key6, pixelgl.Key7, pixelgl.Key8, pixelgl.Key9,
+}
+
+// DefaultCategories are the defect categories offered after a Fail.
+var DefaultCategories = []string{"dead pixel", "stuck pixel", "bright dot", "mura", "backlight bleed", "color", "flicker", "mechanical", "other"}
+
+type result struct {
+	Time     time.Time
+	Serial   string
+	Step     int
+	Test     string
+	Verdict  string // "pass", "fail" or, for step 0, the unit verdict
+	Category string
+	Viewed   time.Duration
+}
+
+// Inspector runs the production-line flow: the operator enters a unit
+// serial, then judges every step of a fixed plan with Pass or Fail. A step's
+// duration is its minimum viewing time; verdicts are ignored until it passes.
+// There is no way back to the menu while it runs.
+type Inspector struct {
+	Categories  []string
+	ResultsPath string
+	Report      *report.Recorder // optional; also receives the verdicts
+
+	plan      *playlist.Player
+	steps     int
+	serial    string
+	entering  bool // typing the serial
+	choosing  bool // Fail pressed, waiting for a category
+	step      int
+	stepStart time.Time
+	failed    bool
+	last      string // outcome of the previous unit, shown on the serial screen
+	err       error  // last error writing results
+}
+
+func New(plan *playlist.Playlist, resultsPath string) *Inspector {
+	plan.Loop = false
+	plan.Shuffle = false
+	return &Inspector{
+		Categories:  DefaultCategories,
+		ResultsPath: resultsPath,
+		plan:        playlist.NewPlayer(plan),
+		steps:       len(plan.Steps),
+		entering:    true,
+	}
+}
+
+// Current returns the test being judged, or nil at the serial prompt.
+func (in *Inspector) Current() core.ScreenTest {
+	if in.entering {
+		return nil
+	}
+	return in.plan.Current()
+}
+
+// Frame handles input and draws one frame. It reports whether the operator
+// asked to quit, which is only possible from the serial prompt.
+func (in *Inspector) Frame(ctx *core.WindowContext) (quit bool) {
+	win := ctx.Win
+	now := time.Now()
+
+	if in.entering {
+		if win.JustPressed(pixelgl.KeyEscape) && in.serial == "" {
+			return true
+		}
+		in.serial += win.Typed()
+		if (win.JustPressed(pixelgl.KeyBackspace) || win.Repeated(pixelgl.KeyBackspace)) && len(in.serial) > 0 {
+			_, size := utf8.DecodeLastRuneInString(in.serial)
+			in.serial = in.serial[:len(in.serial)-size]
+		}
+		if win.JustPressed(pixelgl.KeyEscape) {
+			in.serial = ""
+		}
+		if (win.JustPressed(pixelgl.KeyEnter) || win.JustPressed(pixelgl.KeyKPEnter)) && strings.TrimSpace(in.serial) != "" {
+			in.serial = strings.TrimSpace(in.serial)
+			in.entering = false
+			in.failed = false
+			in.step = 1
+			in.stepStart = now
+			in.plan.Start(ctx, now)
+		}
+		in.drawSerialPrompt(ctx)
+		return false
+	}
+
+	if !ctx.TextEntry {
+		if win.JustPressed(pixelgl.KeyF1) {
+			ctx.ShowInfo = !ctx.ShowInfo
+		}
+		if win.JustPressed(pixelgl.KeyEscape) {
+			in.finish(ctx, now, "aborted")
+			return false
+		}
+	}
+
+	// A step that waits for the operator has no minimum viewing time.
+	left := in.plan.Remaining(now)
+	if in.plan.Waiting() {
+		left = 0
+	}
+
+	// The verdict keys are ours for the whole step, even before they count;
+	// the test mustn't also act on them.
+	test := in.plan.Current()
+	ctx.TextEntry = false
+	ctx.InputBlocked = input.JustPressedAny(win, verdictKeys...)
+	test.Run(ctx)
+	ctx.InputBlocked = false
+
+	if left == 0 && !ctx.TextEntry {
+		in.handleVerdict(ctx, test, now)
+	}
+	if in.entering {
+		return false
+	}
+
+	if ctx.ShowInfo {
+		status := fmt.Sprintf("Unit %s, step %d/%d", in.serial, in.step, in.steps)
+		ui.DrawInfo(ctx, test, test.Options(), ctx.Brightness, status)
+	}
+	ui.DrawInstruction(ctx, in.prompt(left))
+	return false
+}
+
+func (in *Inspector) handleVerdict(ctx *core.WindowContext, test core.ScreenTest, now time.Time) {
+	win := ctx.Win
+
+	if in.choosing {
+		category := ""
+		switch {
+		case win.JustPressed(pixelgl.Key0):
+		default:
+			for i := range in.Categories {
+				if i < 9 && win.JustPressed(pixelgl.Key1+pixelgl.Button(i)) {
+					category = in.Categories[i]
+				}
+			}
+			if category == "" {
+				return
+			}
+		}
+		in.choosing = false
+		in.failed = true
+		in.record(test, "fail", category, now)
+		in.next(ctx, now)
+		return
+	}
+
+	if win.JustPressed(pixelgl.KeyP) {
+		in.record(test, "pass", "", now)
+		in.next(ctx, now)
+	} else if win.JustPressed(pixelgl.KeyX) {
+		in.choosing = true
+	}
+}
+
+func (in *Inspector) next(ctx *core.WindowContext, now time.Time) {
+	in.plan.Next(ctx, now)
+	if !in.plan.Active() {
+		verdict := "pass"
+		if in.failed {
+			verdict = "fail"
+		}
+		in.finish(ctx, now, verdict)
+		return
+	}
+	in.step++
+	in.stepStart = now
+}
+
+// finish records the unit verdict and goes back to the serial prompt.
+func (in *Inspector) finish(ctx *core.WindowContext, now time.Time, verdict string) {
+	in.plan.Stop(ctx)
+	in.choosing = false
+	in.save(result{Time: now, Serial: in.serial, Test: "unit", Verdict: verdict})
+	in.last = fmt.Sprintf("Unit %s: %s", in.serial, strings.ToUpper(verdict))
+	in.serial = ""
+	in.entering = true
+}
+
+func (in *Inspector) record(test core.ScreenTest, verdict, category string, now time.Time) {
+	in.save(result{
+		Time:     now,
+		Serial:   in.serial,
+		Step:     in.step,
+		Test:     core.TestID(test),
+		Verdict:  verdict,
+		Category: category,
+		Viewed:   now.Sub(in.stepStart),
+	})
+}
+
+func (in *Inspector) save(r result) {
+	if in.Report != nil {
+		in.Report.AddVerdict(report.Verdict{Time: r.Time, Serial: r.Serial, Test: r.Test, Verdict: r.Verdict, Category: r.Category})
+	}
+	in.err = appendResult(in.ResultsPath, r)
+}
+
+func (in *Inspector) prompt(left time.Duration) string {
+	var lines []string
+	if instr := in.plan.Instruction(); instr != "" {
+		lines = append(lines, instr)
+	}
+	switch {
+	case left > 0:
+		lines = append(lines, fmt.Sprintf("Keep looking: Pass/Fail in %.0fs", left.Seconds()+0.5))
+	case in.choosing:
+		var cats []string
+		for i, c := range in.Categories {
+			if i < 9 {
+				cats = append(cats, fmt.Sprintf("%d: %s", i+1, c))
+			}
+		}
+		lines = append(lines, "Defect category: "+strings.Join(cats, ", ")+", 0: none")
+	default:
+		lines = append(lines, "P: Pass, X: Fail, ESC: abort unit")
+	}
+	if in.err != nil {
+		lines = append(lines, "RESULTS NOT SAVED: "+in.err.Error())
+	}
+	return strings.Join(lines, " - ")
+}
+
+func (in *Inspector) drawSerialPrompt(ctx *core.WindowContext) {
+	bounds := ctx.Win.Bounds()
+	lines := []string{
+		"INSPECTION",
+		"Scan or type unit serial, then Enter",
+		"> " + in.serial + "_",
+	}
+	if in.last != "" {
+		lines = append(lines, "", in.last)
+	}
+	if in.err != nil {
+		lines = append(lines, "RESULTS NOT SAVED: "+in.err.Error())
+	}
+
+	scale := 3.0
+	lineH := ui.Atlas.LineHeight() * scale * 1.5
+	y := bounds.H()/2 + float64(len(lines))*lineH/2
+	for _, line := range lines {
+		txt := text.New(pixel.ZV, ui.Atlas)
+		txt.Color = colornames.White
+		if strings.HasSuffix(line, "FAIL") || strings.HasPrefix(line, "RESULTS") {
+			txt.Color = colornames.Red
+		} else if strings.HasSuffix(line, "PASS") {
+			txt.Color = colornames.Lime
+		}
+		fmt.Fprint(txt, line)
+		x := (bounds.W() - txt.Bounds().W()*scale) / 2
+		txt.Draw(ctx.Win, pixel.IM.Scaled(pixel.ZV, scale).Moved(pixel.V(x, y)))
+		y -= lineH
+	}
+}
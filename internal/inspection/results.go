@@ -0,0 +1,40 @@
+package inspection
+
+import (
+	"encoding/csv"
+	"os"
+	"strconv"
+	"time"
+)
+
+var resultsHeader = []string{"time", "serial", "step", "test", "verdict", "category", "viewed_s"}
+
+// appendResult appends one row to the CSV results file, writing the header
+// first when the file is new. The file is reopened for every row so a
+// crash or power cut loses at most the verdict being written.
+func appendResult(path string, r result) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	if info, err := f.Stat(); err == nil && info.Size() == 0 {
+		w.Write(resultsHeader)
+	}
+	w.Write([]string{
+		r.Time.Format(time.RFC3339),
+		r.Serial,
+		strconv.Itoa(r.Step),
+		r.Test,
+		r.Verdict,
+		r.Category,
+		strconv.FormatFloat(r.Viewed.Seconds(), 'f', 1, 64),
+	})
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
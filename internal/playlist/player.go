@@ -0,0 +1,189 @@
+package playlist
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/core"
+)
+
+// Player steps through a playlist in real time. It only decides which test
+// is current; the main loop still runs and draws it.
+type Player struct {
+	list    *Playlist
+	steps   []Step // the playlist's steps in playing order
+	index   int
+	cycles  int // completed passes through the playlist
+	active  bool
+	paused  bool
+	started time.Time     // when the current step was last resumed
+	shown   time.Duration // time the step was shown before that
+	err     error         // last error applying step options
+
+	// What the current step's options replaced, put back when it ends.
+	savedTest       core.ScreenTest
+	saved           map[string]string
+	savedBrightness float64
+}
+
+func NewPlayer(list *Playlist) *Player {
+	return &Player{list: list}
+}
+
+func (p *Player) Active() bool { return p.active }
+func (p *Player) Paused() bool { return p.paused }
+
+// Current returns the test of the current step.
+func (p *Player) Current() core.ScreenTest {
+	return p.steps[p.index].Test
+}
+
+// Cycles returns how many times the playlist has been played through.
+func (p *Player) Cycles() int { return p.cycles }
+
+// Start begins playback at the first step.
+func (p *Player) Start(ctx *core.WindowContext, now time.Time) {
+	p.active = true
+	p.paused = false
+	p.cycles = 0
+	p.shuffle()
+	p.enter(ctx, 0, now)
+}
+
+// Stop ends playback and puts back the settings the current step changed.
+func (p *Player) Stop(ctx *core.WindowContext) {
+	p.restore(ctx)
+	p.active = false
+	p.paused = false
+}
+
+func (p *Player) TogglePause(now time.Time) {
+	if p.paused {
+		p.started = now
+	} else {
+		p.shown += now.Sub(p.started)
+	}
+	p.paused = !p.paused
+}
+
+// Next skips to the following step, stopping after the last one unless the
+// playlist loops.
+func (p *Player) Next(ctx *core.WindowContext, now time.Time) {
+	i := p.index + 1
+	if i >= len(p.steps) {
+		p.cycles++
+		if !p.list.Loop {
+			p.Stop(ctx)
+			return
+		}
+		p.shuffle()
+		i = 0
+	}
+	p.enter(ctx, i, now)
+}
+
+// Prev goes back one step, wrapping to the last.
+func (p *Player) Prev(ctx *core.WindowContext, now time.Time) {
+	p.enter(ctx, (p.index-1+len(p.steps))%len(p.steps), now)
+}
+
+// Waiting reports whether the current step waits for a keypress.
+func (p *Player) Waiting() bool {
+	return p.steps[p.index].Wait
+}
+
+// Instruction returns the operator instruction of the current step.
+func (p *Player) Instruction() string {
+	return p.steps[p.index].Instruction
+}
+
+// Update advances when the current step's time is up.
+func (p *Player) Update(ctx *core.WindowContext, now time.Time) {
+	if p.active && !p.paused && !p.Waiting() && p.Remaining(now) <= 0 {
+		p.Next(ctx, now)
+	}
+}
+
+// Remaining returns how long the current step is still shown.
+func (p *Player) Remaining(now time.Time) time.Duration {
+	shown := p.shown
+	if !p.paused {
+		shown += now.Sub(p.started)
+	}
+	if left := p.steps[p.index].Duration - shown; left > 0 {
+		return left
+	}
+	return 0
+}
+
+// Status is the line shown in the info overlay.
+func (p *Player) Status(now time.Time) string {
+	s := fmt.Sprintf("Slideshow %d/%d: %.0fs left", p.index+1, len(p.steps), p.Remaining(now).Seconds())
+	if p.Waiting() {
+		s = fmt.Sprintf("Slideshow %d/%d: press Space to continue", p.index+1, len(p.steps))
+	}
+	if p.paused {
+		s += " (paused)"
+	}
+	if p.err != nil {
+		s += " - " + p.err.Error()
+	}
+	return s
+}
+
+// shuffle sets the playing order. The playlist itself keeps its order.
+func (p *Player) shuffle() {
+	if p.steps == nil {
+		p.steps = append([]Step(nil), p.list.Steps...)
+	}
+	if !p.list.Shuffle {
+		return
+	}
+	steps := p.steps
+	rand.Shuffle(len(steps), func(i, j int) { steps[i], steps[j] = steps[j], steps[i] })
+}
+
+func (p *Player) enter(ctx *core.WindowContext, i int, now time.Time) {
+	p.restore(ctx)
+	p.index = i
+	p.started = now
+	p.shown = 0
+	p.err = nil
+
+	step := p.steps[i]
+	p.savedTest = step.Test
+	p.saved = core.Settings(step.Test)
+	p.savedBrightness = ctx.Brightness
+	keys := make([]string, 0, len(step.Options))
+	for k := range step.Options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := core.SetOption(ctx, step.Test, k, step.Options[k]); err != nil {
+			p.err = err
+		}
+	}
+}
+
+// restore puts back the settings and brightness from before the current
+// step's options were applied.
+func (p *Player) restore(ctx *core.WindowContext) {
+	if p.savedTest == nil {
+		return
+	}
+	if c, ok := p.savedTest.(core.Configurable); ok {
+		keys := make([]string, 0, len(p.saved))
+		for k := range p.saved {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			c.SetOption(k, p.saved[k])
+		}
+	}
+	ctx.Brightness = p.savedBrightness
+	p.savedTest, p.saved = nil, nil
+}
@@ -0,0 +1,64 @@
+package playlist_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/playlist"
+)
+
+func TestPlayerRestoresStepOptions(t *testing.T) {
+	list, err := playlist.Parse("checkerboard size=4,brightness=0.5; white; checkerboard", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := list.Steps[0].Test
+	before := core.Settings(board)["size"]
+	if before == "4" {
+		t.Fatalf("checkerboard already has size 4")
+	}
+
+	ctx := &core.WindowContext{Brightness: 1}
+	show := playlist.NewPlayer(list)
+	now := time.Now()
+	show.Start(ctx, now)
+	if got := core.Settings(board)["size"]; got != "4" || ctx.Brightness != 0.5 {
+		t.Fatalf("step 1: size %s, brightness %v; want 4, 0.5", got, ctx.Brightness)
+	}
+
+	show.Next(ctx, now)
+	if got := core.Settings(board)["size"]; got != before || ctx.Brightness != 1 {
+		t.Errorf("step 2: size %s, brightness %v; want %s, 1", got, ctx.Brightness, before)
+	}
+
+	show.Prev(ctx, now)
+	show.Stop(ctx)
+	if got := core.Settings(board)["size"]; got != before || ctx.Brightness != 1 {
+		t.Errorf("stopped: size %s, brightness %v; want %s, 1", got, ctx.Brightness, before)
+	}
+}
+
+func TestPlayerShuffleKeepsPlaylistOrder(t *testing.T) {
+	list, err := playlist.Parse("white; black; red; green; blue; checkerboard", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Shuffle = true
+	order := append([]playlist.Step(nil), list.Steps...)
+
+	ctx := &core.WindowContext{Brightness: 1}
+	show := playlist.NewPlayer(list)
+	now := time.Now()
+	show.Start(ctx, now)
+	for range 2 * len(order) {
+		show.Next(ctx, now)
+	}
+	show.Stop(ctx)
+
+	for i, step := range list.Steps {
+		if step.Test != order[i].Test {
+			t.Fatalf("playlist step %d is %s after shuffling, want %s", i+1, core.TestID(step.Test), core.TestID(order[i].Test))
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package playlist
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/core"
+)
+
+// DefaultDuration is used for steps that don't give one.
+const DefaultDuration = 10 * time.Second
+
+// Step shows one test for a while with some options applied first.
+type Step struct {
+	Test        core.ScreenTest
+	Duration    time.Duration
+	Wait        bool              // stay until the operator presses a key instead
+	Options     map[string]string // applied in key order with core.SetOption
+	Instruction string            // shown to the operator during the step
+}
+
+type Playlist struct {
+	Steps   []Step
+	Loop    bool
+	Shuffle bool // reorder the steps randomly on every pass
+}
+
+// Default runs every test in menu order for d each.
+func Default(tests []core.ScreenTest, d time.Duration) *Playlist {
+	p := &Playlist{Loop: true}
+	for _, t := range tests {
+		p.Steps = append(p.Steps, Step{Test: t, Duration: d})
+	}
+	return p
+}
+
+// Parse reads the -playlist syntax: steps separated by ";", each a test ID
+// with an optional "@duration" followed by comma-separated key=value options,
+// for example "checkerboard@5s size=4; zone-plate@20s mode=moire lines,angle=2.5".
+func Parse(spec string, loop bool) (*Playlist, error) {
+	p := &Playlist{Loop: loop}
+	for i, field := range strings.Split(spec, ";") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		step, err := parseStep(field)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		p.Steps = append(p.Steps, step)
+	}
+	if len(p.Steps) == 0 {
+		return nil, fmt.Errorf("playlist has no steps")
+	}
+	return p, nil
+}
+
+func parseStep(field string) (Step, error) {
+	head, rest, _ := strings.Cut(field, " ")
+	ref, dur, hasDur := strings.Cut(head, "@")
+
+	step := Step{Duration: DefaultDuration}
+	t, ok := core.FindTest(ref)
+	if !ok {
+		return step, fmt.Errorf("unknown test %q", ref)
+	}
+	step.Test = t
+
+	if hasDur {
+		d, err := time.ParseDuration(dur)
+		if err != nil {
+			return step, err
+		}
+		if d <= 0 {
+			return step, fmt.Errorf("duration must be positive, got %s", dur)
+		}
+		step.Duration = d
+	}
+
+	if rest = strings.TrimSpace(rest); rest != "" {
+		step.Options = map[string]string{}
+		for _, opt := range strings.Split(rest, ",") {
+			key, value, ok := strings.Cut(opt, "=")
+			key = strings.TrimSpace(key)
+			if !ok {
+				return step, fmt.Errorf("option %q is not key=value", opt)
+			}
+			value = strings.TrimSpace(value)
+			if err := core.ValidateOption(t, key, value); err != nil {
+				return step, err
+			}
+			step.Options[key] = value
+		}
+	}
+	return step, nil
+}
@@ -0,0 +1,83 @@
+package playlist_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/playlist"
+	_ "github.com/keshon/screen-tester/internal/tests" // auto-register tests
+)
+
+func TestParse(t *testing.T) {
+	type step struct {
+		test     string
+		duration time.Duration
+		options  map[string]string
+	}
+	cases := []struct {
+		spec  string
+		steps []step
+		err   string // substring of the error, or "" for success
+	}{
+		{
+			spec:  "checkerboard@5s size=4; zone-plate@20s mode=circular,contrast=50",
+			steps: []step{{"small-checkerboard", 5 * time.Second, map[string]string{"size": "4"}}, {"zone-plate", 20 * time.Second, map[string]string{"mode": "circular", "contrast": "50"}}},
+		},
+		{
+			spec:  "zone-plate mode=moire lines, angle=2.5",
+			steps: []step{{"zone-plate", playlist.DefaultDuration, map[string]string{"mode": "moire lines", "angle": "2.5"}}},
+		},
+		{
+			spec:  " white ;; Black@1m ",
+			steps: []step{{"white", playlist.DefaultDuration, nil}, {"black", time.Minute, nil}},
+		},
+		{spec: "", err: "no steps"},
+		{spec: " ; ", err: "no steps"},
+		{spec: "white; nothing", err: `step 2: unknown test "nothing"`},
+		{spec: "white@soon", err: "step 1: time: invalid duration"},
+		{spec: "white@0s", err: "duration must be positive"},
+		{spec: "checkerboard size", err: `option "size" is not key=value`},
+		{spec: "checkerboard colour=red", err: "unknown option"},
+		{spec: "white size=4", err: "unknown option"},
+		{spec: "zone-plate contrast=42", err: "step 1: contrast: 42% is not one of"},
+		{spec: "checkerboard size=big", err: "size: strconv.Atoi"},
+	}
+	for _, c := range cases {
+		p, err := playlist.Parse(c.spec, true)
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("Parse(%q) error = %v, want %q", c.spec, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", c.spec, err)
+			continue
+		}
+		if !p.Loop || len(p.Steps) != len(c.steps) {
+			t.Errorf("Parse(%q) = %d steps, loop %v; want %d, loop true", c.spec, len(p.Steps), p.Loop, len(c.steps))
+			continue
+		}
+		for i, want := range c.steps {
+			got := p.Steps[i]
+			if core.TestID(got.Test) != want.test || got.Duration != want.duration || !equalOptions(got.Options, want.options) {
+				t.Errorf("Parse(%q) step %d = %s@%s %v, want %s@%s %v", c.spec, i+1,
+					core.TestID(got.Test), got.Duration, got.Options, want.test, want.duration, want.options)
+			}
+		}
+	}
+}
+
+func equalOptions(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
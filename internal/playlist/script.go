@@ -0,0 +1,208 @@
+package playlist
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/core"
+)
+
+// scriptStep is one entry of the "steps" array in a script file:
+//
+//	{
+//	  "loop": false,
+//	  "steps": [
+//	    {"test": "small-checkerboard", "options": {"size": 2}, "duration": "20s",
+//	     "instruction": "Check for flicker at 2 px"},
+//	    {"test": "black", "brightness": 1, "wait": true,
+//	     "instruction": "Count lit pixels, then press Space"}
+//	  ]
+//	}
+type scriptStep struct {
+	Test        string                 `json:"test"`
+	Options     map[string]interface{} `json:"options"`
+	Brightness  *float64               `json:"brightness"`
+	Duration    string                 `json:"duration"`
+	Wait        bool                   `json:"wait"`
+	Instruction string                 `json:"instruction"`
+}
+
+// LoadScript reads a JSON script file. Errors carry the file name and line.
+func LoadScript(path string) (*Playlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ParseScript(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", path, err)
+	}
+	return p, nil
+}
+
+// ParseScript parses and validates a script; errors start with the line number.
+func ParseScript(data []byte) (*Playlist, error) {
+	p := &Playlist{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	fail := func(offset int64, err error) (*Playlist, error) {
+		var syntax *json.SyntaxError
+		var typ *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntax):
+			offset = syntax.Offset
+		case errors.As(err, &typ):
+			offset = typ.Offset
+		case errors.Is(err, io.EOF):
+			offset = int64(len(data))
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("%d: %w", lineAt(data, offset), err)
+	}
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return fail(dec.InputOffset(), err)
+	}
+	for dec.More() {
+		keyOffset := skipSeparators(data, dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			return fail(keyOffset, err)
+		}
+		switch key := tok.(string); key {
+		case "loop":
+			if err := dec.Decode(&p.Loop); err != nil {
+				return fail(keyOffset, err)
+			}
+		case "steps":
+			if err := expectDelim(dec, '['); err != nil {
+				return fail(dec.InputOffset(), err)
+			}
+			for dec.More() {
+				start := skipSeparators(data, dec.InputOffset())
+				var s scriptStep
+				if err := dec.Decode(&s); err != nil {
+					return fail(start, err)
+				}
+				step, keyName, err := s.toStep()
+				if err != nil {
+					return fail(keyOffsetIn(data, start, dec.InputOffset(), keyName), err)
+				}
+				p.Steps = append(p.Steps, step)
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return fail(dec.InputOffset(), err)
+			}
+		default:
+			return fail(keyOffset, fmt.Errorf("unknown field %q", key))
+		}
+	}
+
+	if len(p.Steps) == 0 {
+		return nil, fmt.Errorf("%d: script has no steps", lineAt(data, int64(len(data))))
+	}
+	return p, nil
+}
+
+// toStep validates the step against the registry. On error it also returns
+// the JSON key the error is about, to point at its line.
+func (s scriptStep) toStep() (Step, string, error) {
+	step := Step{Duration: DefaultDuration, Wait: s.Wait, Instruction: s.Instruction}
+
+	t, ok := core.FindTest(s.Test)
+	if !ok {
+		if s.Test == "" {
+			return step, "", fmt.Errorf("step has no test")
+		}
+		return step, "test", fmt.Errorf("unknown test %q", s.Test)
+	}
+	step.Test = t
+
+	switch {
+	case s.Wait && s.Duration != "":
+		return step, "wait", fmt.Errorf("give either duration or wait, not both")
+	case s.Duration != "":
+		d, err := time.ParseDuration(s.Duration)
+		if err != nil {
+			return step, "duration", err
+		}
+		if d <= 0 {
+			return step, "duration", fmt.Errorf("duration must be positive, got %s", s.Duration)
+		}
+		step.Duration = d
+	}
+
+	if len(s.Options) > 0 || s.Brightness != nil {
+		step.Options = map[string]string{}
+	}
+	if s.Brightness != nil {
+		if *s.Brightness < 0 || *s.Brightness > 1 {
+			return step, "brightness", fmt.Errorf("brightness must be between 0 and 1, got %g", *s.Brightness)
+		}
+		step.Options["brightness"] = strconv.FormatFloat(*s.Brightness, 'f', -1, 64)
+	}
+
+	keys := make([]string, 0, len(s.Options))
+	for k := range s.Options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := fmt.Sprint(s.Options[k])
+		if err := core.ValidateOption(t, k, value); err != nil {
+			return step, k, err
+		}
+		step.Options[k] = value
+	}
+	return step, "", nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("expected %q, got %v", want, tok)
+	}
+	return nil
+}
+
+// skipSeparators moves offset past whitespace and commas to the next value.
+func skipSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// keyOffsetIn finds the quoted key within data[start:end], falling back to start.
+func keyOffsetIn(data []byte, start, end int64, key string) int64 {
+	if key == "" {
+		return start
+	}
+	if i := bytes.Index(data[start:end], []byte(strconv.Quote(key))); i >= 0 {
+		return start + int64(i)
+	}
+	return start
+}
+
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
@@ -0,0 +1,72 @@
+package playlist_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/playlist"
+)
+
+func TestParseScript(t *testing.T) {
+	const valid = `{
+  "loop": true,
+  "steps": [
+    {"test": "small-checkerboard", "options": {"size": 2}, "duration": "20s",
+     "instruction": "Check for flicker at 2 px"},
+    {"test": "black", "brightness": 1, "wait": true,
+     "instruction": "Count lit pixels, then press Space"}
+  ]
+}`
+	p, err := playlist.ParseScript([]byte(valid))
+	if err != nil {
+		t.Fatalf("valid script: %v", err)
+	}
+	if !p.Loop || len(p.Steps) != 2 {
+		t.Fatalf("valid script = %d steps, loop %v; want 2, loop true", len(p.Steps), p.Loop)
+	}
+	if s := p.Steps[0]; core.TestID(s.Test) != "small-checkerboard" || s.Options["size"] != "2" || s.Duration.String() != "20s" {
+		t.Errorf("step 1 = %s %v %s", core.TestID(s.Test), s.Options, s.Duration)
+	}
+	if s := p.Steps[1]; !s.Wait || s.Options["brightness"] != "1" || s.Instruction == "" {
+		t.Errorf("step 2 = wait %v %v %q", s.Wait, s.Options, s.Instruction)
+	}
+
+	cases := []struct {
+		name   string
+		script string
+		err    string // expected error prefix: line number and message start
+	}{
+		{"syntax", "{\n  \"steps\": [\n    {\"test\": \"black\",}\n  ]\n}", "3: invalid character"},
+		{"truncated", "{\n  \"steps\": [\n    {\"test\": \"black\"}", "3: unexpected end of JSON input"},
+		{"unknown field", "{\n  \"loop\": true,\n  \"speed\": 2\n}", `3: unknown field "speed"`},
+		{"wrong type", "{\n  \"loop\": \"yes\"\n}", "2: json: cannot unmarshal"},
+		{"no steps", "{\n  \"steps\": []\n}", "3: script has no steps"},
+		{"no test", "{\"steps\": [\n  {\"duration\": \"5s\"}\n]}", "2: step has no test"},
+		{"unknown test", "{\"steps\": [\n  {\"duration\": \"5s\",\n   \"test\": \"nothing\"}\n]}", `3: unknown test "nothing"`},
+		{"bad duration", "{\"steps\": [\n  {\"test\": \"black\",\n   \"duration\": \"soon\"}\n]}", "3: time: invalid duration"},
+		{"wait and duration", "{\"steps\": [\n  {\"test\": \"black\", \"duration\": \"5s\",\n   \"wait\": true}\n]}", "3: give either duration or wait"},
+		{"brightness range", "{\"steps\": [\n  {\"test\": \"black\",\n   \"brightness\": 2}\n]}", "3: brightness must be between 0 and 1"},
+		{"unknown option", "{\"steps\": [\n  {\"test\": \"black\",\n   \"options\": {\"size\": 2}}\n]}", "3: unknown option"},
+		{"bad option value", "{\"steps\": [\n  {\"test\": \"zone-plate\", \"options\": {\n    \"mode\": \"circular\",\n    \"contrast\": 42}}\n]}", "4: contrast: 42% is not one of"},
+		{"bad option choice", "{\"steps\": [\n  {\"test\": \"checkerboard\",\n   \"options\": {\"size\": \"big\"}}\n]}", "3: size: strconv.Atoi"},
+	}
+	for _, c := range cases {
+		_, err := playlist.ParseScript([]byte(c.script))
+		if err == nil || !strings.HasPrefix(err.Error(), c.err) {
+			t.Errorf("%s: error = %v, want prefix %q", c.name, err, c.err)
+		}
+	}
+}
+
+func TestParseScriptLeavesOptions(t *testing.T) {
+	test, _ := core.FindTest("small-checkerboard")
+	before := core.Settings(test)["size"]
+	script := `{"steps": [{"test": "small-checkerboard", "options": {"size": 7}}]}`
+	if _, err := playlist.ParseScript([]byte(script)); err != nil {
+		t.Fatal(err)
+	}
+	if after := core.Settings(test)["size"]; after != before {
+		t.Errorf("loading changed size from %s to %s", before, after)
+	}
+}
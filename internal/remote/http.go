@@ -0,0 +1,214 @@
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ListenHTTP serves the control API on addr, which must be a loopback
+// address. It returns once the listener is open; requests are served in the
+// background and every change is run on the main thread through q.
+//
+//	GET  /api/tests       registered tests with their settable options
+//	GET  /api/state       current test, brightness, overlay and options
+//	POST /api/test        {"test": "zone-plate"}
+//	POST /api/next        next test
+//	POST /api/prev        previous test
+//	POST /api/options     {"size": 4, "brightness": 0.5}
+//	POST /api/brightness  {"brightness": 0.5}
+//	POST /api/info        {"show": false}, or no body to toggle
+//	POST /api/note        {"text": "backlight bleed bottom left"}
+//	POST /api/report      write the session report; answers {"files": [...]}
+//	POST /api/screenshot  {"overlays": true}, or no body for the bare pattern;
+//	                      answers {"file": "..."} once the frame is drawn
+//
+// The other POSTs answer with the new state. Requests must name a loopback
+// host and carry no Origin, which keeps web pages in a local browser out.
+func ListenHTTP(addr string, q *Queue) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if !isLoopback(host) {
+		return fmt.Errorf("%s is not a loopback address", addr)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tests", q.handle(func(t *Target) (interface{}, error) {
+		return t.List(), nil
+	}))
+	mux.HandleFunc("GET /api/state", q.handle(func(t *Target) (interface{}, error) {
+		return t.State(), nil
+	}))
+	mux.HandleFunc("POST /api/test", q.handleBody(func(t *Target, body map[string]interface{}) error {
+		ref, ok := body["test"].(string)
+		if !ok {
+			return errors.New(`body must be {"test": "<id>"}`)
+		}
+		return t.Select(ref)
+	}))
+	mux.HandleFunc("POST /api/next", q.handleBody(func(t *Target, _ map[string]interface{}) error {
+		t.Step(1)
+		return nil
+	}))
+	mux.HandleFunc("POST /api/prev", q.handleBody(func(t *Target, _ map[string]interface{}) error {
+		t.Step(-1)
+		return nil
+	}))
+	mux.HandleFunc("POST /api/options", q.handleBody(setOptions))
+	mux.HandleFunc("POST /api/brightness", q.handleBody(func(t *Target, body map[string]interface{}) error {
+		b, ok := body["brightness"]
+		if !ok {
+			return errors.New(`body must be {"brightness": <0..1>}`)
+		}
+		return t.Set("brightness", fmt.Sprint(b))
+	}))
+	mux.HandleFunc("POST /api/info", q.handleBody(func(t *Target, body map[string]interface{}) error {
+		show, ok := body["show"].(bool)
+		if !ok {
+			show = !t.Ctx.ShowInfo
+		}
+		t.Ctx.ShowInfo = show
+		return nil
+	}))
+	mux.HandleFunc("POST /api/note", q.handleBody(func(t *Target, body map[string]interface{}) error {
+		text, _ := body["text"].(string)
+		return t.Note(text)
+	}))
+	mux.HandleFunc("POST /api/report", q.handle(func(t *Target) (interface{}, error) {
+		paths, err := t.Report()
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]string{"files": paths}, nil
+	}))
+
+	mux.HandleFunc("POST /api/screenshot", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Overlays bool `json:"overlays"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			writeJSON(w, nil, badRequest{err})
+			return
+		}
+		v, err := q.Do(func(t *Target) (interface{}, error) {
+			later, err := t.Screenshot(body.Overlays)
+			if err != nil {
+				return nil, badRequest{err}
+			}
+			return later, nil
+		})
+		if err == nil {
+			v = map[string]interface{}{"file": v}
+		}
+		writeJSON(w, v, err)
+	})
+
+	go http.Serve(ln, localOnly(mux))
+	return nil
+}
+
+// localOnly refuses requests a web page could make through a browser on
+// this machine: a DNS-rebound name shows up as a foreign Host, and
+// cross-site requests carry an Origin. Local tools such as curl send
+// neither.
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		switch {
+		case !isLoopback(host):
+			writeJSON(w, nil, forbidden{fmt.Errorf("host %q is not a loopback address", r.Host)})
+		case r.Header.Get("Origin") != "":
+			writeJSON(w, nil, forbidden{errors.New("browser requests are not accepted")})
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// setOptions applies the options in key order and stops at the first error;
+// earlier options stay applied.
+func setOptions(t *Target, body map[string]interface{}) error {
+	keys := make([]string, 0, len(body))
+	for k := range body {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := t.Set(k, fmt.Sprint(body[k])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *Queue) handle(fn func(*Target) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		v, err := q.Do(fn)
+		writeJSON(w, v, err)
+	}
+}
+
+// handleBody decodes an optional JSON object body, runs fn on the main
+// thread and replies with the resulting state.
+func (q *Queue) handleBody(fn func(*Target, map[string]interface{}) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			writeJSON(w, nil, badRequest{err})
+			return
+		}
+		v, err := q.Do(func(t *Target) (interface{}, error) {
+			if err := fn(t, body); err != nil {
+				return nil, badRequest{err}
+			}
+			return t.State(), nil
+		})
+		writeJSON(w, v, err)
+	}
+}
+
+// badRequest marks errors caused by the request rather than the server.
+type badRequest struct{ error }
+
+// forbidden marks requests from where the API isn't offered.
+type forbidden struct{ error }
+
+func writeJSON(w http.ResponseWriter, v interface{}, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		status := http.StatusInternalServerError
+		var bad badRequest
+		var denied forbidden
+		switch {
+		case errors.As(err, &bad):
+			status = http.StatusBadRequest
+		case errors.As(err, &denied):
+			status = http.StatusForbidden
+		}
+		w.WriteHeader(status)
+		v = map[string]string{"error": err.Error()}
+	}
+	json.NewEncoder(w).Encode(v)
+}
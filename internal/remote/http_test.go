@@ -0,0 +1,39 @@
+package remote
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocalOnly(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	cases := []struct {
+		host   string
+		origin string
+		want   int
+	}{
+		{"127.0.0.1:8765", "", http.StatusOK},
+		{"localhost:8765", "", http.StatusOK},
+		{"LOCALHOST", "", http.StatusOK},
+		{"[::1]:8765", "", http.StatusOK},
+		{"127.0.0.2", "", http.StatusOK},
+		{"evil.example:8765", "", http.StatusForbidden},
+		{"192.168.1.10:8765", "", http.StatusForbidden},
+		{"", "", http.StatusForbidden},
+		{"127.0.0.1:8765", "http://evil.example", http.StatusForbidden},
+		{"127.0.0.1:8765", "null", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest("GET", "/api/state", nil)
+		req.Host = c.host
+		if c.origin != "" {
+			req.Header.Set("Origin", c.origin)
+		}
+		rec := httptest.NewRecorder()
+		localOnly(ok).ServeHTTP(rec, req)
+		if rec.Code != c.want {
+			t.Errorf("Host %q, Origin %q: status %d, want %d", c.host, c.origin, rec.Code, c.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+)
+
+// ServeLines runs the line protocol: one command per line, answered with a
+// line starting with "OK" or "ERR". It returns when r is exhausted.
+//
+//	list                   test IDs, space separated
+//	state                  current state as JSON
+//	test <id or name>      show a test
+//	next, prev             step through the tests
+//	set <key> <value>      set an option of the current test
+//	brightness <0..1>      set the brightness
+//	info [on|off]          show, hide or toggle the info overlay
+//	note <text>            add an operator note to the session report
+//	report                 write the session report, replying with the paths
+//	screenshot [overlays]  save the current pattern as a PNG, replying with the path
+//	quit                   close the tester
+func ServeLines(r io.Reader, w io.Writer, q *Queue) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		reply, err := q.Do(func(t *Target) (interface{}, error) {
+			return execLine(t, line)
+		})
+		if err != nil {
+			fmt.Fprintf(w, "ERR %s\n", strings.ReplaceAll(err.Error(), "\n", " "))
+			continue
+		}
+		if s, _ := reply.(string); s != "" {
+			fmt.Fprintf(w, "OK %s\n", s)
+		} else {
+			fmt.Fprintln(w, "OK")
+		}
+		if ack, ok := reply.(quitting); ok {
+			close(ack)
+		}
+	}
+}
+
+// quitting is the reply to quit; it is closed once the reply is written.
+type quitting chan struct{}
+
+// ListenUnix serves the line protocol on a Unix domain socket, one
+// goroutine per connection. A stale socket from an earlier run is removed,
+// but any other file at path is left alone and reported.
+func ListenUnix(path string, q *Queue) error {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				ServeLines(conn, conn, q)
+			}()
+		}
+	}()
+	return nil
+}
+
+func execLine(t *Target, line string) (interface{}, error) {
+	cmd, args, _ := strings.Cut(line, " ")
+	args = strings.TrimSpace(args)
+
+	switch strings.ToLower(cmd) {
+	case "list":
+		ids := make([]string, len(t.Tests))
+		for i, test := range t.List() {
+			ids[i] = test.ID
+		}
+		return strings.Join(ids, " "), nil
+	case "state":
+		data, err := json.Marshal(t.State())
+		return string(data), err
+	case "test":
+		return "", t.Select(args)
+	case "next":
+		t.Step(1)
+		return "", nil
+	case "prev":
+		t.Step(-1)
+		return "", nil
+	case "set":
+		key, value, ok := strings.Cut(args, " ")
+		if !ok {
+			return "", errors.New("usage: set <key> <value>")
+		}
+		return "", t.Set(key, strings.TrimSpace(value))
+	case "brightness":
+		return "", t.Set("brightness", args)
+	case "info":
+		switch strings.ToLower(args) {
+		case "":
+			t.Ctx.ShowInfo = !t.Ctx.ShowInfo
+		case "on":
+			t.Ctx.ShowInfo = true
+		case "off":
+			t.Ctx.ShowInfo = false
+		default:
+			return "", errors.New("usage: info [on|off]")
+		}
+		return "", nil
+	case "note":
+		return "", t.Note(args)
+	case "report":
+		paths, err := t.Report()
+		return strings.Join(paths, " "), err
+	case "screenshot":
+		if args != "" && args != "overlays" {
+			return "", errors.New("usage: screenshot [overlays]")
+		}
+		later, err := t.Screenshot(args == "overlays")
+		if err != nil {
+			return "", err
+		}
+		return later, nil
+	case "quit":
+		t.Quit = true
+		t.quitReplied = make(quitting)
+		return t.quitReplied, nil
+	}
+	return "", fmt.Errorf("unknown command %q", cmd)
+}
@@ -0,0 +1,143 @@
+package remote
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/input"
+	_ "github.com/keshon/screen-tester/internal/tests" // auto-register tests
+)
+
+func TestExecLine(t *testing.T) {
+	var tests []core.ScreenTest
+	for _, id := range []string{"black", "small-checkerboard", "zone-plate"} {
+		test, ok := core.FindTest(id)
+		if !ok {
+			t.Fatalf("test %s is not registered", id)
+		}
+		tests = append(tests, test)
+	}
+	menu := true
+	target := &Target{
+		Ctx:      &core.WindowContext{Brightness: 1, ShowInfo: true},
+		Tests:    tests,
+		Controls: &input.TestInput{},
+		ShowMenu: &menu,
+	}
+
+	// The commands run in order against the same target.
+	cases := []struct {
+		line  string
+		reply string // prefix of the reply
+		err   string // substring of the error, or "" for success
+		check func() error
+	}{
+		{line: "list", reply: "black small-checkerboard zone-plate"},
+		{line: "test checkerboard", check: showing(target, "small-checkerboard")},
+		{line: "set size 4"},
+		{line: "state", reply: `{"test":"small-checkerboard","name":"Small Checkerboard","menu":false`},
+		{line: "set size", err: "usage: set <key> <value>"},
+		{line: "set colour red", err: "unknown option"},
+		{line: "set size big", err: "size:"},
+		{line: "NEXT", check: showing(target, "zone-plate")},
+		{line: "next", check: showing(target, "black")},
+		{line: "prev", check: showing(target, "zone-plate")},
+		{line: "test Black", check: showing(target, "black")},
+		{line: "test white", err: `unknown test "white"`}, // registered, but not offered
+		{line: "test nothing", err: `unknown test "nothing"`},
+		{line: "brightness 0.5", check: func() error {
+			if target.Ctx.Brightness != 0.5 {
+				return fmt.Errorf("brightness %g", target.Ctx.Brightness)
+			}
+			return nil
+		}},
+		{line: "brightness bright", err: "brightness:"},
+		{line: "info off", check: info(target, false)},
+		{line: "info", check: info(target, true)},
+		{line: "info maybe", err: "usage: info [on|off]"},
+		{line: "note dust at the top", err: "session report is disabled"},
+		{line: "report", err: "session report is disabled"},
+		{line: "screenshot sideways", err: "usage: screenshot [overlays]"},
+		{line: "screenshot", err: "screenshots are disabled"},
+		{line: "dance", err: `unknown command "dance"`},
+		{line: "quit", check: func() error {
+			if !target.Quit {
+				return fmt.Errorf("not quitting")
+			}
+			return nil
+		}},
+	}
+	for _, c := range cases {
+		reply, err := execLine(target, c.line)
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("%q: error = %v, want %q", c.line, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", c.line, err)
+			continue
+		}
+		if s, _ := reply.(string); !strings.HasPrefix(s, c.reply) {
+			t.Errorf("%q = %q, want prefix %q", c.line, s, c.reply)
+		}
+		if c.check != nil {
+			if err := c.check(); err != nil {
+				t.Errorf("%q: %v", c.line, err)
+			}
+		}
+	}
+}
+
+func showing(target *Target, id string) func() error {
+	return func() error {
+		if got := core.TestID(target.Current()); got != id || *target.ShowMenu {
+			return fmt.Errorf("showing %s (menu %v), want %s", got, *target.ShowMenu, id)
+		}
+		return nil
+	}
+}
+
+func info(target *Target, want bool) func() error {
+	return func() error {
+		if target.Ctx.ShowInfo != want {
+			return fmt.Errorf("info overlay %v, want %v", target.Ctx.ShowInfo, want)
+		}
+		return nil
+	}
+}
+
+func TestServeLinesRepliesBeforeQuit(t *testing.T) {
+	q := NewQueue()
+	target := &Target{}
+	var out strings.Builder
+	go ServeLines(strings.NewReader("quit\n"), &out, q)
+
+	for !target.Quit {
+		q.Drain(target)
+		time.Sleep(time.Millisecond)
+	}
+	target.AwaitQuitReply()
+	if out.String() != "OK\n" {
+		t.Errorf("reply before exit = %q, want OK", out.String())
+	}
+}
+
+func TestListenUnixKeepsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ListenUnix(path, NewQueue()); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Errorf("ListenUnix on a regular file: %v, want not a socket", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "keep me" {
+		t.Errorf("file after ListenUnix = %q, %v", data, err)
+	}
+}
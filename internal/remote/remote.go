@@ -0,0 +1,261 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/input"
+)
+
+// ErrTimeout is returned when the main loop doesn't pick a command up,
+// for example while the window is closing.
+var ErrTimeout = errors.New("main loop did not respond")
+
+var commandTimeout = 5 * time.Second
+
+// Target is the state remote commands act on. It belongs to the main loop
+// and is only touched from Queue.Drain, on the pixelgl main thread.
+type Target struct {
+	Ctx      *core.WindowContext
+	Tests    []core.ScreenTest
+	Controls *input.TestInput
+	ShowMenu *bool
+	Quit     bool // set by a quit command; the main loop exits
+
+	// Optional hooks for the session report.
+	AddNote      func(text string)
+	ExportReport func() ([]string, error)
+
+	// Optional hook that saves the next frame of the current test and
+	// calls done with the file once it has been drawn.
+	SaveScreenshot func(overlays bool, done func(path string, err error))
+
+	quitReplied quitting
+}
+
+// AwaitQuitReply gives the client that sent quit up to a second to get its
+// reply before the main loop exits.
+func (t *Target) AwaitQuitReply() {
+	if t.quitReplied == nil {
+		return
+	}
+	select {
+	case <-t.quitReplied:
+	case <-time.After(time.Second):
+	}
+}
+
+// Queue hands commands from server goroutines to the main loop.
+type Queue struct {
+	cmds chan command
+}
+
+type command struct {
+	fn    func(*Target) (interface{}, error)
+	reply chan reply
+	state *atomic.Int32 // pending, running or cancelled
+}
+
+const (
+	pending int32 = iota
+	running
+	cancelled
+)
+
+type reply struct {
+	value interface{}
+	err   error
+}
+
+// Later is returned by a command whose result is only known on a later
+// frame, such as a screenshot that needs the test drawn first. Do waits
+// for Done before answering.
+type Later struct {
+	reply chan reply
+}
+
+func NewLater() *Later {
+	return &Later{reply: make(chan reply, 1)}
+}
+
+func (l *Later) Done(value interface{}, err error) {
+	l.reply <- reply{value, err}
+}
+
+func NewQueue() *Queue {
+	return &Queue{cmds: make(chan command, 16)}
+}
+
+// Do runs fn on the main thread and waits for its result. A command that
+// times out before the main loop gets to it is dropped, so it can't take
+// effect after the client was told it failed; one that has started is
+// waited for.
+func (q *Queue) Do(fn func(*Target) (interface{}, error)) (interface{}, error) {
+	c := command{fn: fn, reply: make(chan reply, 1), state: new(atomic.Int32)}
+	timer := time.NewTimer(commandTimeout)
+	defer timer.Stop()
+
+	select {
+	case q.cmds <- c:
+	case <-timer.C:
+		return nil, ErrTimeout
+	}
+	var r reply
+	select {
+	case r = <-c.reply:
+	case <-timer.C:
+		if c.state.CompareAndSwap(pending, cancelled) {
+			return nil, ErrTimeout
+		}
+		r = <-c.reply
+	}
+	if later, ok := r.value.(*Later); ok && r.err == nil {
+		select {
+		case r = <-later.reply:
+		case <-timer.C:
+			return nil, ErrTimeout
+		}
+	}
+	return r.value, r.err
+}
+
+// Drain runs the pending commands; the main loop calls it once per frame.
+func (q *Queue) Drain(t *Target) {
+	for {
+		select {
+		case c := <-q.cmds:
+			if !c.state.CompareAndSwap(pending, running) {
+				continue // timed out while queued
+			}
+			v, err := c.fn(t)
+			c.reply <- reply{v, err}
+		default:
+			return
+		}
+	}
+}
+
+// TestInfo describes a registered test.
+type TestInfo struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Options     map[string]string `json:"options"` // settable options and their values
+}
+
+// State is the current display state as reported to clients.
+type State struct {
+	Test       string            `json:"test"`
+	Name       string            `json:"name"`
+	Menu       bool              `json:"menu"`
+	Slideshow  bool              `json:"slideshow"`
+	Brightness float64           `json:"brightness"`
+	ShowInfo   bool              `json:"show_info"`
+	Width      int               `json:"width"`
+	Height     int               `json:"height"`
+	Options    map[string]string `json:"options"`
+}
+
+func (t *Target) Current() core.ScreenTest {
+	return t.Tests[t.Controls.Current]
+}
+
+func (t *Target) List() []TestInfo {
+	list := make([]TestInfo, len(t.Tests))
+	for i, test := range t.Tests {
+		list[i] = TestInfo{
+			ID:          core.TestID(test),
+			Name:        test.Name(),
+			Description: test.Description(),
+			Options:     core.Settings(test),
+		}
+	}
+	return list
+}
+
+func (t *Target) State() State {
+	test := t.Current()
+	return State{
+		Test:       core.TestID(test),
+		Name:       test.Name(),
+		Menu:       *t.ShowMenu,
+		Slideshow:  t.Controls.Slideshow != nil && t.Controls.Slideshow.Active(),
+		Brightness: t.Ctx.Brightness,
+		ShowInfo:   t.Ctx.ShowInfo,
+		Width:      t.Ctx.ScreenWidth,
+		Height:     t.Ctx.ScreenHeight,
+		Options:    core.OptionValues(test),
+	}
+}
+
+// Select shows the test with the given ID or name. Like a keypress, it
+// takes over from a running slideshow.
+func (t *Target) Select(ref string) error {
+	if found, ok := core.FindTest(ref); ok {
+		for i, test := range t.Tests {
+			if test == found {
+				t.show(i)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unknown test %q", ref)
+}
+
+// Step moves delta tests forwards or backwards, wrapping around.
+func (t *Target) Step(delta int) {
+	n := len(t.Tests)
+	t.show(((t.Controls.Current+delta)%n + n) % n)
+}
+
+// Set sets an option of the current test, or the brightness.
+func (t *Target) Set(key, value string) error {
+	return core.SetOption(t.Ctx, t.Current(), key, value)
+}
+
+// Note adds an operator note to the session report.
+func (t *Target) Note(text string) error {
+	if t.AddNote == nil {
+		return errors.New("session report is disabled")
+	}
+	if text == "" {
+		return errors.New("empty note")
+	}
+	t.AddNote(text)
+	return nil
+}
+
+// Report writes the session report and returns the file paths.
+func (t *Target) Report() ([]string, error) {
+	if t.ExportReport == nil {
+		return nil, errors.New("session report is disabled")
+	}
+	return t.ExportReport()
+}
+
+// Screenshot saves the next frame of the current test; the Later
+// resolves to the file path.
+func (t *Target) Screenshot(overlays bool) (*Later, error) {
+	if t.SaveScreenshot == nil {
+		return nil, errors.New("screenshots are disabled")
+	}
+	if *t.ShowMenu {
+		return nil, errors.New("no test is showing")
+	}
+	later := NewLater()
+	t.SaveScreenshot(overlays, func(path string, err error) {
+		later.Done(path, err)
+	})
+	return later, nil
+}
+
+func (t *Target) show(i int) {
+	if t.Controls.Slideshow != nil {
+		t.Controls.Slideshow.Stop(t.Ctx)
+	}
+	t.Controls.Current = i
+	*t.ShowMenu = false
+}
@@ -0,0 +1,48 @@
+package remote
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDoDropsTimedOutCommands(t *testing.T) {
+	defer func(d time.Duration) { commandTimeout = d }(commandTimeout)
+	commandTimeout = 10 * time.Millisecond
+
+	q := NewQueue()
+	ran := false
+	if _, err := q.Do(func(*Target) (interface{}, error) {
+		ran = true
+		return nil, nil
+	}); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Do without a main loop: %v, want ErrTimeout", err)
+	}
+	q.Drain(&Target{})
+	if ran {
+		t.Error("a command that timed out ran on the next Drain")
+	}
+}
+
+func TestDoWaitsForRunningCommands(t *testing.T) {
+	defer func(d time.Duration) { commandTimeout = d }(commandTimeout)
+	commandTimeout = 10 * time.Millisecond
+
+	q := NewQueue()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			q.Drain(&Target{})
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	v, err := q.Do(func(*Target) (interface{}, error) {
+		time.Sleep(3 * commandTimeout) // started in time, finishes late
+		return "done", nil
+	})
+	if err != nil || v != "done" {
+		t.Errorf("Do = %v, %v; want done, nil", v, err)
+	}
+	<-done
+}
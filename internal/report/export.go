@@ -0,0 +1,206 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/defects"
+)
+
+// mapWidth is the width of the defect map in the HTML report.
+const mapWidth = 960
+
+// Write saves the report as JSON, CSV and HTML in dir, named after the
+// session start time, and returns the file paths.
+func Write(dir string, rep Report) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	base := filepath.Join(dir, "report-"+rep.Started.Format("20060102-150405"))
+
+	writers := []struct {
+		ext   string
+		write func(*bytes.Buffer, Report) error
+	}{
+		{".json", writeJSON},
+		{".csv", writeCSV},
+		{".html", writeHTML},
+	}
+	var paths []string
+	for _, w := range writers {
+		var buf bytes.Buffer
+		if err := w.write(&buf, rep); err != nil {
+			return paths, fmt.Errorf("%s: %w", w.ext, err)
+		}
+		if err := os.WriteFile(base+w.ext, buf.Bytes(), 0644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, base+w.ext)
+	}
+	return paths, nil
+}
+
+func writeJSON(buf *bytes.Buffer, rep Report) error {
+	enc := json.NewEncoder(buf)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+// writeCSV writes one flat table with a row per view, defect, note and
+// verdict; the first column says which.
+func writeCSV(buf *bytes.Buffer, rep Report) error {
+	w := csv.NewWriter(buf)
+	w.Write([]string{"record", "time", "test", "duration_s", "brightness", "options", "x", "y", "kind", "serial", "verdict", "category", "text"})
+
+	for _, v := range rep.Views {
+		w.Write([]string{"view", stamp(v.Start), v.Test, seconds(v.Duration), strconv.FormatFloat(v.Brightness, 'f', 2, 64), formatOptions(v.Options), "", "", "", "", "", "", ""})
+	}
+	for _, d := range rep.Defects {
+		w.Write([]string{"defect", stamp(d.Time), d.Test, "", "", "", strconv.Itoa(d.X), strconv.Itoa(d.Y), d.Kind, "", "", "", ""})
+	}
+	for _, n := range rep.Notes {
+		w.Write([]string{"note", stamp(n.Time), n.Test, "", "", "", "", "", "", "", "", "", n.Text})
+	}
+	for _, v := range rep.Verdicts {
+		w.Write([]string{"verdict", stamp(v.Time), v.Test, "", "", "", "", "", "", v.Serial, v.Verdict, v.Category, ""})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"stamp":   stamp,
+	"seconds": seconds,
+	"options": formatOptions,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Report.App}} report {{stamp .Report.Started}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; background: #fafafa; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #eee; }
+img { border: 1px solid #888; image-rendering: pixelated; }
+.pass { color: #080; } .fail, .aborted { color: #c00; }
+</style>
+</head>
+<body>
+<h1>Screen test report</h1>
+<p>{{.Report.App}}, {{stamp .Report.Started}} to {{stamp .Report.Finished}}</p>
+
+<h2>Monitor</h2>
+<table>
+<tr><th>Name</th><td>{{.Report.Monitor.Name}}</td></tr>
+<tr><th>Resolution</th><td>{{.Report.Monitor.Width}} x {{.Report.Monitor.Height}}</td></tr>
+<tr><th>Refresh rate</th><td>{{printf "%.2f" .Report.Monitor.RefreshRate}} Hz{{if .Report.Monitor.MeasuredHz}} (measured {{printf "%.2f" .Report.Monitor.MeasuredHz}} Hz){{end}}</td></tr>
+{{if .Report.Monitor.PhysicalW}}<tr><th>Physical size</th><td>{{printf "%.0f" .Report.Monitor.PhysicalW}} x {{printf "%.0f" .Report.Monitor.PhysicalH}} mm</td></tr>{{end}}
+{{if .Report.Monitor.BitsRed}}<tr><th>Bit depth</th><td>{{.Report.Monitor.BitsRed}}/{{.Report.Monitor.BitsGreen}}/{{.Report.Monitor.BitsBlue}}</td></tr>{{end}}
+</table>
+
+<h2>Defects ({{len .Report.Defects}})</h2>
+<img src="{{.Map}}" width="{{.MapWidth}}" alt="defect map">
+{{if .Report.Defects}}
+<table>
+<tr><th>X</th><th>Y</th><th>Type</th><th>Test</th><th>Time</th></tr>
+{{range .Report.Defects}}<tr><td>{{.X}}</td><td>{{.Y}}</td><td>{{.Kind}}</td><td>{{.Test}}</td><td>{{stamp .Time}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{if .Report.Verdicts}}
+<h2>Verdicts</h2>
+<table>
+<tr><th>Time</th><th>Serial</th><th>Test</th><th>Verdict</th><th>Category</th></tr>
+{{range .Report.Verdicts}}<tr><td>{{stamp .Time}}</td><td>{{.Serial}}</td><td>{{.Test}}</td><td class="{{.Verdict}}">{{.Verdict}}</td><td>{{.Category}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{if .Report.Notes}}
+<h2>Notes</h2>
+<table>
+<tr><th>Time</th><th>Test</th><th>Note</th></tr>
+{{range .Report.Notes}}<tr><td>{{stamp .Time}}</td><td>{{.Test}}</td><td>{{.Text}}</td></tr>
+{{end}}</table>
+{{end}}
+
+<h2>Tests viewed</h2>
+<table>
+<tr><th>Start</th><th>Test</th><th>Duration</th><th>Brightness</th><th>Options</th></tr>
+{{range .Report.Views}}<tr><td>{{stamp .Start}}</td><td>{{.Name}}</td><td>{{seconds .Duration}} s</td><td>{{printf "%.2f" .Brightness}}</td><td>{{options .Options}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func writeHTML(buf *bytes.Buffer, rep Report) error {
+	var img bytes.Buffer
+	if err := png.Encode(&img, defectMap(rep)); err != nil {
+		return err
+	}
+	return htmlTemplate.Execute(buf, map[string]interface{}{
+		"Report":   rep,
+		"Map":      template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(img.Bytes())),
+		"MapWidth": mapWidth,
+	})
+}
+
+// defectMap draws the panel scaled to mapWidth with a dot per defect.
+// Marks are drawn larger than the scaled pixel so single pixels stay visible.
+func defectMap(rep Report) image.Image {
+	w, h := rep.Monitor.Width, rep.Monitor.Height
+	if w <= 0 || h <= 0 {
+		w, h = 16, 9
+	}
+	scale := float64(mapWidth) / float64(w)
+	img := image.NewRGBA(image.Rect(0, 0, mapWidth, int(float64(h)*scale+0.5)))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{20, 20, 20, 255}), image.Point{}, draw.Src)
+
+	for _, m := range rep.Defects {
+		c := defects.KindColor(m.Kind)
+		cx, cy := int(float64(m.X)*scale), int(float64(m.Y)*scale)
+		for dy := -3; dy <= 3; dy++ {
+			for dx := -3; dx <= 3; dx++ {
+				if dx*dx+dy*dy <= 9 {
+					img.Set(cx+dx, cy+dy, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+func formatOptions(opts map[string]string) string {
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + opts[k]
+	}
+	return strings.Join(parts, ", ")
+}
+
+func stamp(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 1, 64)
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/defects"
+)
+
+func sampleReport() Report {
+	start := time.Date(2026, 10, 19, 15, 30, 0, 0, time.UTC)
+	return Report{
+		App:      "Screen Tester",
+		Started:  start,
+		Finished: start.Add(time.Minute),
+		Monitor:  Monitor{Name: "Panel", Width: 1920, Height: 1080, RefreshRate: 60},
+		Views: []View{
+			{Test: "small-checkerboard", Name: "Small Checkerboard", Start: start, Duration: 12500 * time.Millisecond, Brightness: 1, Options: map[string]string{"size": "4"}},
+		},
+		Defects:  []defects.Mark{{X: 10, Y: 20, Kind: "dead", Test: "white", Time: start}},
+		Notes:    []Note{{Time: start, Test: "white", Text: "bleed <bottom left>, \"faint\""}},
+		Verdicts: []Verdict{{Time: start, Serial: "SN1", Test: "black", Verdict: "fail", Category: "dust"}},
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	rep := sampleReport()
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, rep); err != nil {
+		t.Fatal(err)
+	}
+	var back Report
+	if err := json.Unmarshal(buf.Bytes(), &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, rep) {
+		t.Errorf("round trip:\n got %+v\nwant %+v", back, rep)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, sampleReport()); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"record", "time", "test", "duration_s", "brightness", "options", "x", "y", "kind", "serial", "verdict", "category", "text"},
+		{"view", "2026-10-19 15:30:00", "small-checkerboard", "12.5", "1.00", "size=4", "", "", "", "", "", "", ""},
+		{"defect", "2026-10-19 15:30:00", "white", "", "", "", "10", "20", "dead", "", "", "", ""},
+		{"note", "2026-10-19 15:30:00", "white", "", "", "", "", "", "", "", "", "", "bleed <bottom left>, \"faint\""},
+		{"verdict", "2026-10-19 15:30:00", "black", "", "", "", "", "", "", "SN1", "fail", "dust", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows:\n got %q\nwant %q", rows, want)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeHTML(&buf, sampleReport()); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+	for _, want := range []string{
+		"<td>1920 x 1080</td>",
+		"data:image/png;base64,",
+		`<td class="fail">fail</td>`,
+		"bleed &lt;bottom left&gt;, &#34;faint&#34;",
+		"<td>12.5 s</td><td>1.00</td><td>size=4</td>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("HTML lacks %q", want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := Write(filepath.Join(dir, "reports"), sampleReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range paths {
+		if _, err := os.Stat(p); err != nil {
+			t.Error(err)
+		}
+		names = append(names, filepath.Base(p))
+	}
+	want := []string{"report-20261019-153000.json", "report-20261019-153000.csv", "report-20261019-153000.html"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("files %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,125 @@
+package report
+
+import (
+	"time"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/defects"
+)
+
+type Monitor struct {
+	Name        string  `json:"name"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	RefreshRate float64 `json:"refresh_rate_hz"`
+	MeasuredHz  float64 `json:"measured_hz,omitempty"`
+	PhysicalW   float64 `json:"physical_width_mm,omitempty"`
+	PhysicalH   float64 `json:"physical_height_mm,omitempty"`
+	BitsRed     int     `json:"bits_red,omitempty"`
+	BitsGreen   int     `json:"bits_green,omitempty"`
+	BitsBlue    int     `json:"bits_blue,omitempty"`
+}
+
+// View is one continuous stretch of time a test was on screen. Options and
+// brightness are the values when it was left.
+type View struct {
+	Test       string            `json:"test"`
+	Name       string            `json:"name"`
+	Start      time.Time         `json:"start"`
+	Duration   time.Duration     `json:"duration_ns"`
+	Brightness float64           `json:"brightness"`
+	Options    map[string]string `json:"options,omitempty"`
+}
+
+type Note struct {
+	Time time.Time `json:"time"`
+	Test string    `json:"test,omitempty"`
+	Text string    `json:"text"`
+}
+
+type Verdict struct {
+	Time     time.Time `json:"time"`
+	Serial   string    `json:"serial,omitempty"`
+	Test     string    `json:"test"`
+	Verdict  string    `json:"verdict"`
+	Category string    `json:"category,omitempty"`
+}
+
+// Report is everything recorded during one run of the tester.
+type Report struct {
+	App      string         `json:"app"`
+	Started  time.Time      `json:"started"`
+	Finished time.Time      `json:"finished"`
+	Monitor  Monitor        `json:"monitor"`
+	Views    []View         `json:"views"`
+	Defects  []defects.Mark `json:"defects"`
+	Notes    []Note         `json:"notes"`
+	Verdicts []Verdict      `json:"verdicts"`
+}
+
+// Recorder collects the report as the session goes on.
+type Recorder struct {
+	Report
+
+	current core.ScreenTest
+	since   time.Time
+}
+
+func NewRecorder(app string, monitor Monitor, now time.Time) *Recorder {
+	return &Recorder{Report: Report{App: app, Started: now, Monitor: monitor}}
+}
+
+// Track is called every frame with the test on screen, or nil in the menu.
+func (r *Recorder) Track(ctx *core.WindowContext, t core.ScreenTest, now time.Time) {
+	if t == r.current {
+		return
+	}
+	r.closeView(ctx, now)
+	r.current = t
+	r.since = now
+}
+
+func (r *Recorder) AddNote(t core.ScreenTest, text string, now time.Time) {
+	n := Note{Time: now, Text: text}
+	if t != nil {
+		n.Test = core.TestID(t)
+	}
+	r.Notes = append(r.Notes, n)
+}
+
+func (r *Recorder) AddVerdict(v Verdict) {
+	r.Verdicts = append(r.Verdicts, v)
+}
+
+// Snapshot returns the report up to now, including the test still on
+// screen and the given defect marks.
+func (r *Recorder) Snapshot(ctx *core.WindowContext, marks []defects.Mark, now time.Time) Report {
+	rep := r.Report
+	rep.Views = append([]View(nil), r.Views...)
+	if r.current != nil {
+		rep.Views = append(rep.Views, r.view(ctx, now))
+	}
+	rep.Defects = append([]defects.Mark(nil), marks...)
+	rep.Finished = now
+	if ctx.Frames != nil {
+		rep.Monitor.MeasuredHz = ctx.Frames.Stats().MeasuredHz
+	}
+	return rep
+}
+
+func (r *Recorder) closeView(ctx *core.WindowContext, now time.Time) {
+	if r.current != nil {
+		r.Views = append(r.Views, r.view(ctx, now))
+	}
+}
+
+func (r *Recorder) view(ctx *core.WindowContext, now time.Time) View {
+	return View{
+		Test:       core.TestID(r.current),
+		Name:       r.current.Name(),
+		Start:      r.since,
+		Duration:   now.Sub(r.since),
+		Brightness: ctx.Brightness,
+		Options:    core.OptionValues(r.current),
+	}
+}
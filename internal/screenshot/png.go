@@ -0,0 +1,64 @@
+package screenshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+)
+
+// ihdrEnd is the offset just past the PNG signature and the IHDR chunk,
+// which image/png always writes first.
+const ihdrEnd = 8 + 4 + 4 + 13 + 4
+
+// encode writes img as a PNG with a tEXt chunk per keyword/text pair,
+// placed right after IHDR so readers find them before the image data.
+func encode(w io.Writer, img image.Image, text [][2]string) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+
+	if _, err := w.Write(data[:ihdrEnd]); err != nil {
+		return err
+	}
+	for _, kv := range text {
+		if err := writeText(w, kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(data[ihdrEnd:])
+	return err
+}
+
+// writeText writes a tEXt chunk. tEXt is Latin-1, so other characters
+// are replaced with '?'.
+func writeText(w io.Writer, keyword, text string) error {
+	body := append([]byte("tEXt"), latin1(keyword)...)
+	body = append(body, 0)
+	body = append(body, latin1(text)...)
+
+	var length, crc [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(body)-4))
+	binary.BigEndian.PutUint32(crc[:], crc32.ChecksumIEEE(body))
+	for _, b := range [][]byte{length[:], body, crc[:]} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func latin1(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xff {
+			r = '?'
+		}
+		out = append(out, byte(r))
+	}
+	return out
+}
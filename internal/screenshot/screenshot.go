@@ -0,0 +1,201 @@
+package screenshot
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/faiface/pixel/pixelgl"
+
+	"github.com/keshon/screen-tester/internal/core"
+)
+
+// Meta describes what a screenshot shows. It goes into the file name and
+// the PNG text chunks.
+type Meta struct {
+	Test       string // test ID
+	Name       string
+	Options    map[string]string
+	Brightness float64
+	Overlays   bool
+	Software   string
+	Time       time.Time
+}
+
+// Camera saves screenshots on request. A request is served on the next
+// frame the test is drawn, either right after the test or, if it asks for
+// overlays, after the overlays.
+type Camera struct {
+	Dir      string
+	Software string
+
+	pending []request
+}
+
+type request struct {
+	overlays bool
+	done     func(path string, err error)
+}
+
+func NewCamera(dir, software string) *Camera {
+	return &Camera{Dir: dir, Software: software}
+}
+
+// Request asks for a screenshot; done, if not nil, gets the file path.
+func (c *Camera) Request(overlays bool, done func(path string, err error)) {
+	c.pending = append(c.pending, request{overlays, done})
+}
+
+// Capture serves the pending requests whose overlay setting matches. The
+// main loop calls it with overlays false after running the test and with
+// overlays true after drawing everything else.
+func (c *Camera) Capture(ctx *core.WindowContext, test core.ScreenTest, overlays bool) {
+	var served, rest []request
+	for _, r := range c.pending {
+		if r.overlays == overlays {
+			served = append(served, r)
+		} else {
+			rest = append(rest, r)
+		}
+	}
+	if len(served) == 0 {
+		return
+	}
+	c.pending = rest
+
+	path, err := Save(c.Dir, Grab(ctx.Win), Meta{
+		Test:       core.TestID(test),
+		Name:       test.Name(),
+		Options:    core.OptionValues(test),
+		Brightness: ctx.Brightness,
+		Overlays:   overlays,
+		Software:   c.Software,
+		Time:       time.Now(),
+	})
+	for _, r := range served {
+		if r.done != nil {
+			r.done(path, err)
+		}
+	}
+}
+
+// Grab returns what has been drawn to the window so far this frame, at
+// the native resolution of its framebuffer.
+func Grab(win *pixelgl.Window) *image.RGBA {
+	tex := win.Canvas().Texture()
+	w, h := tex.Width(), tex.Height()
+	pixels := win.Canvas().Pixels()
+
+	// OpenGL rows go bottom-up. The panel shows no alpha, so it's made
+	// opaque rather than left to whatever blending produced.
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	stride := 4 * w
+	for y := 0; y < h; y++ {
+		row := img.Pix[y*stride : (y+1)*stride]
+		copy(row, pixels[(h-1-y)*stride:(h-y)*stride])
+		for x := 3; x < stride; x += 4 {
+			row[x] = 255
+		}
+	}
+	return img
+}
+
+// Save writes img as a PNG in dir and returns its path.
+func Save(dir string, img image.Image, m Meta) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, FileName(m))
+	return path, Write(path, img, m)
+}
+
+// Write writes img to path as a PNG with m in its text chunks.
+func Write(path string, img image.Image, m Meta) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := encode(f, img, m.textChunks(img.Bounds())); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// FileName is the Slug followed by the time, e.g.
+// "small-checkerboard_size-4_20261019-153012-250.png".
+func FileName(m Meta) string {
+	stamp := fmt.Sprintf("%s-%03d", m.Time.Format("20060102-150405"), m.Time.Nanosecond()/int(time.Millisecond))
+	return Slug(m) + "_" + stamp + ".png"
+}
+
+// maxSlug keeps file names, with the time and extension added, well under
+// the 255-byte limit of common file systems.
+const maxSlug = 200
+
+// Slug names the test and its options in a file-name-safe way, e.g.
+// "small-checkerboard_size-4". Brightness is only named when it isn't full.
+// Long slugs are cut short; the text chunks still have every option.
+func Slug(m Meta) string {
+	parts := []string{sanitize(m.Test)}
+	for _, k := range sortedKeys(m.Options) {
+		parts = append(parts, sanitize(k)+"-"+sanitize(m.Options[k]))
+	}
+	if m.Brightness != 1 {
+		parts = append(parts, "brightness-"+strconv.FormatFloat(m.Brightness, 'f', 2, 64))
+	}
+	if m.Overlays {
+		parts = append(parts, "overlays")
+	}
+	slug := strings.Join(parts, "_")
+	if len(slug) > maxSlug {
+		slug = strings.TrimRight(slug[:maxSlug], "_-")
+	}
+	return slug
+}
+
+func (m Meta) textChunks(bounds image.Rectangle) [][2]string {
+	opts := make([]string, 0, len(m.Options))
+	for _, k := range sortedKeys(m.Options) {
+		opts = append(opts, k+"="+m.Options[k])
+	}
+	overlays := "no"
+	if m.Overlays {
+		overlays = "yes"
+	}
+	return [][2]string{
+		{"Title", m.Name},
+		{"Software", m.Software},
+		{"Creation Time", m.Time.Format(time.RFC1123Z)},
+		{"Test", m.Test},
+		{"Options", strings.Join(opts, ", ")},
+		{"Brightness", strconv.FormatFloat(m.Brightness, 'f', 2, 64)},
+		{"Resolution", fmt.Sprintf("%dx%d", bounds.Dx(), bounds.Dy())},
+		{"Overlays", overlays},
+	}
+}
+
+// sanitize keeps names portable across file systems, USB sticks included.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		}
+		return '-'
+	}, s)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,97 @@
+package screenshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlug(t *testing.T) {
+	cases := []struct {
+		meta Meta
+		want string
+	}{
+		{Meta{Test: "small-checkerboard", Options: map[string]string{"size": "4"}, Brightness: 1}, "small-checkerboard_size-4"},
+		{Meta{Test: "zone-plate", Options: map[string]string{"mode": "moire lines", "angle": "2.5 deg"}, Brightness: 1}, "zone-plate_angle-2.5-deg_mode-moire-lines"},
+		{Meta{Test: "white", Brightness: 0.5, Overlays: true}, "white_brightness-0.50_overlays"},
+		{Meta{Test: "test-card", Options: map[string]string{"label": "a/b\\c:d"}, Brightness: 1}, "test-card_label-a-b-c-d"},
+	}
+	for _, c := range cases {
+		if got := Slug(c.meta); got != c.want {
+			t.Errorf("Slug(%+v) = %q, want %q", c.meta, got, c.want)
+		}
+	}
+
+	long := Slug(Meta{Test: "test-card", Options: map[string]string{"label": strings.Repeat("x", 500)}, Brightness: 1})
+	if len(long) > maxSlug || !strings.HasPrefix(long, "test-card_label-xxx") {
+		t.Errorf("long slug is %d bytes: %q", len(long), long)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	m := Meta{
+		Test:       "small-checkerboard",
+		Options:    map[string]string{"size": "4"},
+		Brightness: 1,
+		Time:       time.Date(2026, 10, 19, 15, 30, 12, 250*int(time.Millisecond), time.UTC),
+	}
+	if got, want := FileName(m), "small-checkerboard_size-4_20261019-153012-250.png"; got != want {
+		t.Errorf("FileName = %q, want %q", got, want)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(1, 1, color.RGBA{255, 0, 0, 255})
+	text := [][2]string{{"Title", "Small Checkerboard"}, {"Options", "size=4, note=café ★"}}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, img, text); err != nil {
+		t.Fatal(err)
+	}
+
+	// The chunks must be well formed, with the text right after IHDR.
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")) {
+		t.Fatal("no PNG signature")
+	}
+	var types []string
+	var texts []string
+	for off := 8; off < len(data); {
+		n := int(binary.BigEndian.Uint32(data[off:]))
+		body := data[off+4 : off+8+n]
+		if crc := binary.BigEndian.Uint32(data[off+8+n:]); crc != crc32.ChecksumIEEE(body) {
+			t.Errorf("chunk %s: bad CRC", body[:4])
+		}
+		types = append(types, string(body[:4]))
+		if string(body[:4]) == "tEXt" {
+			texts = append(texts, string(body[4:]))
+		}
+		off += 12 + n
+	}
+	if len(types) < 4 || types[0] != "IHDR" || types[1] != "tEXt" || types[2] != "tEXt" || types[len(types)-1] != "IEND" {
+		t.Errorf("chunk order %v", types)
+	}
+	want := []string{"Title\x00Small Checkerboard", "Options\x00size=4, note=caf\xe9 ?"}
+	if len(texts) != len(want) || texts[0] != want[0] || texts[1] != want[1] {
+		t.Errorf("text chunks %q, want %q", texts, want)
+	}
+
+	// And the image must still decode to the same pixels.
+	back, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Bounds() != img.Bounds() {
+		t.Fatalf("bounds %v, want %v", back.Bounds(), img.Bounds())
+	}
+	if r, g, b, _ := back.At(1, 1).RGBA(); r != 0xffff || g != 0 || b != 0 {
+		t.Errorf("pixel (1,1) = %v, want red", back.At(1, 1))
+	}
+}
@@ -0,0 +1,15 @@
+package soak
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// keepAwake asserts that the user is active for a little longer than the
+// interval between calls, which keeps the display on.
+func keepAwake() {
+	cmd := exec.Command("caffeinate", "-u", "-t", strconv.Itoa(int(awakeInterval.Seconds())*2))
+	if err := cmd.Start(); err == nil {
+		go cmd.Wait()
+	}
+}
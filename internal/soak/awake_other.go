@@ -0,0 +1,14 @@
+//go:build !windows && !darwin
+
+package soak
+
+import "os/exec"
+
+// keepAwake resets the X screensaver and DPMS idle timers. Errors are
+// ignored: without xdg-utils the fullscreen window is all we have.
+func keepAwake() {
+	cmd := exec.Command("xdg-screensaver", "reset")
+	if err := cmd.Start(); err == nil {
+		go cmd.Wait()
+	}
+}
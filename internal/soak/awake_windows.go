@@ -0,0 +1,16 @@
+package soak
+
+import "syscall"
+
+const (
+	esSystemRequired  = 0x00000001
+	esDisplayRequired = 0x00000002
+)
+
+var setThreadExecutionState = syscall.NewLazyDLL("kernel32.dll").NewProc("SetThreadExecutionState")
+
+// keepAwake resets the display and system idle timers. Without ES_CONTINUOUS
+// the call is a one-off reset, so it doesn't matter which thread makes it.
+func keepAwake() {
+	setThreadExecutionState.Call(esSystemRequired | esDisplayRequired)
+}
@@ -0,0 +1,110 @@
+package soak
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/core"
+)
+
+// awakeInterval is how often the screensaver and display sleep timers are reset.
+const awakeInterval = 30 * time.Second
+
+// Session logs an unattended soak run: every test change, every recovered
+// panic and a periodic heartbeat, and keeps the display from sleeping.
+type Session struct {
+	Started   time.Time
+	Heartbeat time.Duration
+
+	file      *os.File
+	log       *log.Logger
+	lastBeat  time.Time
+	lastAwake time.Time
+	current   core.ScreenTest
+	panics    int
+	lastPanic string // test and value of the last panic logged in full
+	repeats   int    // panics identical to lastPanic since it was logged
+}
+
+// Start opens the log for appending and writes the first entry.
+func Start(path string, heartbeat time.Duration, now time.Time) (*Session, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{
+		Started:   now,
+		Heartbeat: heartbeat,
+		file:      f,
+		log:       log.New(f, "", log.LstdFlags),
+		lastBeat:  now,
+	}
+	s.log.Printf("soak started")
+	return s, nil
+}
+
+func (s *Session) Close() error {
+	s.logRepeats()
+	s.log.Printf("soak stopped after %s, %d panics", s.Elapsed(time.Now()), s.panics)
+	return s.file.Close()
+}
+
+func (s *Session) Elapsed(now time.Time) time.Duration {
+	return now.Sub(s.Started).Truncate(time.Second)
+}
+
+// Update is called once per frame with the test being shown and the number
+// of completed cycles.
+func (s *Session) Update(ctx *core.WindowContext, t core.ScreenTest, cycles int, now time.Time) {
+	if t != s.current {
+		s.logRepeats()
+		s.lastPanic = ""
+		s.current = t
+		s.log.Printf("test %s", core.TestID(t))
+	}
+
+	if now.Sub(s.lastAwake) >= awakeInterval {
+		s.lastAwake = now
+		keepAwake()
+	}
+
+	if s.Heartbeat > 0 && now.Sub(s.lastBeat) >= s.Heartbeat {
+		s.lastBeat = now
+		s.logRepeats()
+		line := fmt.Sprintf("heartbeat elapsed=%s cycle=%d test=%s panics=%d", s.Elapsed(now), cycles+1, core.TestID(t), s.panics)
+		if ctx.Frames != nil {
+			st := ctx.Frames.Stats()
+			line += fmt.Sprintf(" hz=%.2f dropped=%d", st.MeasuredHz, st.Dropped)
+		}
+		s.log.Print(line)
+	}
+}
+
+// Recovered logs a panic from a test's Run. A test that panics every frame
+// gets its stack logged once; the repeats are only counted.
+func (s *Session) Recovered(t core.ScreenTest, r interface{}, stack []byte) {
+	s.panics++
+	what := fmt.Sprintf("%s: %v", core.TestID(t), r)
+	if what == s.lastPanic {
+		s.repeats++
+		return
+	}
+	s.logRepeats()
+	s.lastPanic = what
+	s.log.Printf("panic in %s\n%s", what, strings.TrimSpace(string(stack)))
+}
+
+func (s *Session) logRepeats() {
+	if s.repeats > 0 {
+		s.log.Printf("panic in %s repeated %d times", s.lastPanic, s.repeats)
+		s.repeats = 0
+	}
+}
+
+// Status is the line shown in the info overlay.
+func (s *Session) Status(cycles int, now time.Time) string {
+	return fmt.Sprintf("Soak: %s elapsed, cycle %d, %d panics", s.Elapsed(now), cycles+1, s.panics)
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"image/color"
+	"strconv"
 
 	"github.com/keshon/screen-tester/internal/core"
 
@@ -52,7 +53,9 @@ func (t *Checkerboard) HandleKeys(ctx *core.WindowContext) {
 }
 
 func (t *Checkerboard) Run(ctx *core.WindowContext) {
-	t.HandleKeys(ctx)
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
 
 	size := t.opts.Extra["size"].(int)
 	bounds := ctx.Win.Bounds()
@@ -76,6 +79,23 @@ func (t *Checkerboard) Run(ctx *core.WindowContext) {
 	sprite.Draw(ctx.Win, pixel.IM.Moved(bounds.Center()))
 }
 
+func (t *Checkerboard) SetOption(key, value string) error {
+	if key != "size" {
+		return core.ErrUnknownOption
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	t.getSize()
+	t.setSize(size)
+	return nil
+}
+
+func (t *Checkerboard) Settings() map[string]string {
+	return map[string]string{"size": strconv.Itoa(t.getSize())}
+}
+
 func (t *Checkerboard) getSize() int {
 	if t.opts.Extra == nil {
 		t.opts.Extra = map[string]interface{}{}
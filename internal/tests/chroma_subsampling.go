@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/ui"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+type ChromaSubsampling struct{}
+
+// chromaPairs put foregrounds on backgrounds of similar luma but very
+// different chroma, so only the chroma channel carries the detail.
+var chromaPairs = []struct {
+	name   string
+	fg, bg color.RGBA
+}{
+	{"red on blue", color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}},
+	{"blue on red", color.RGBA{0, 0, 255, 255}, color.RGBA{255, 0, 0, 255}},
+	{"magenta on green", color.RGBA{255, 0, 255, 255}, color.RGBA{0, 160, 0, 255}},
+	{"green on magenta", color.RGBA{0, 160, 0, 255}, color.RGBA{255, 0, 255, 255}},
+	{"cyan on red", color.RGBA{0, 255, 255, 255}, color.RGBA{255, 0, 0, 255}},
+	{"yellow on blue", color.RGBA{255, 255, 0, 255}, color.RGBA{0, 0, 255, 255}},
+	{"red on gray", color.RGBA{255, 0, 0, 255}, color.RGBA{96, 96, 96, 255}},
+	{"white on black (reference)", color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 255}},
+}
+
+var chromaHelp = []string{
+	"4:4:4 - text and every 1 px line stay crisp in all cells, like the white on black reference.",
+	"4:2:2 - text and vertical lines smear or change color, horizontal lines stay crisp.",
+	"4:2:0 - text, vertical and horizontal lines all smear; thin colored lines may vanish.",
+}
+
+const chromaSample = "The quick brown fox jumps over the lazy dog 0123456789"
+
+func (t *ChromaSubsampling) Name() string { return "Chroma Subsampling" }
+func (t *ChromaSubsampling) Description() string {
+	return "Thin colored text and 1 px lines on contrasting colors; they blur when the signal chain uses 4:2:2 or 4:2:0 instead of 4:4:4"
+}
+func (t *ChromaSubsampling) Order() int { return 44 }
+
+func (t *ChromaSubsampling) Options() core.TestOptions {
+	return core.TestOptions{Brightness: 1.0}
+}
+
+func (t *ChromaSubsampling) HandleKeys(ctx *core.WindowContext) {
+	core.AdjustBrightnessWithKeys(ctx, 0.1)
+}
+
+func (t *ChromaSubsampling) Run(ctx *core.WindowContext) {
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+
+	ctx.Win.Clear(colornames.Black)
+	bounds := ctx.Win.Bounds()
+
+	helpH := float64(len(chromaHelp)+1) * ui.Atlas.LineHeight()
+	cols := 2
+	rows := (len(chromaPairs) + cols - 1) / cols
+	cellW := float64(int(bounds.W() / float64(cols)))
+	cellH := float64(int((bounds.H() - helpH) / float64(rows)))
+
+	imd := imdraw.New(nil)
+	labels := text.New(pixel.ZV, ui.Atlas)
+	labels.Color = colornames.White
+	samples := make([]*text.Text, 0, len(chromaPairs))
+
+	for i, p := range chromaPairs {
+		x0 := float64(i%cols) * cellW
+		top := bounds.H() - helpH - float64(i/cols)*cellH
+		fg := core.AdjustBrightness(p.fg, ctx.Brightness)
+		bg := core.AdjustBrightness(p.bg, ctx.Brightness)
+
+		// Leave a black strip at the top of the cell for its name.
+		patch := pixel.R(x0+4, top-cellH+4, x0+cellW-4, top-18)
+		imd.Color = bg
+		imd.Push(patch.Min, patch.Max)
+		imd.Rectangle(0)
+
+		// Left third: vertical 1 px lines, middle third: horizontal ones,
+		// with 1 px gaps so the pattern is at the full chroma resolution.
+		imd.Color = fg
+		third := float64(int(patch.W() / 3))
+		linesTop := patch.Max.Y - 30
+		for x := patch.Min.X + 8; x < patch.Min.X+third-8; x += 2 {
+			imd.Push(pixel.V(x, patch.Min.Y+8), pixel.V(x+1, linesTop))
+			imd.Rectangle(0)
+		}
+		for y := patch.Min.Y + 8; y < linesTop; y += 2 {
+			imd.Push(pixel.V(patch.Min.X+third+8, y), pixel.V(patch.Min.X+2*third-8, y+1))
+			imd.Rectangle(0)
+		}
+		// Right third: single lines with growing gaps.
+		gap := 1.0
+		for x := patch.Min.X + 2*third + 8; x < patch.Max.X-8; x += gap + 1 {
+			imd.Push(pixel.V(x, patch.Min.Y+8), pixel.V(x+1, linesTop))
+			imd.Rectangle(0)
+			gap++
+		}
+
+		labels.Dot = pixel.V(x0+6, top-14)
+		fmt.Fprint(labels, p.name)
+
+		sample := text.New(pixel.V(patch.Min.X+8, patch.Max.Y-18), ui.Atlas)
+		sample.Color = fg
+		fmt.Fprint(sample, chromaSample)
+		samples = append(samples, sample)
+	}
+
+	imd.Draw(ctx.Win)
+	for _, sample := range samples {
+		sample.Draw(ctx.Win, pixel.IM)
+	}
+	labels.Draw(ctx.Win, pixel.IM)
+
+	help := text.New(pixel.V(10, bounds.H()-ui.Atlas.LineHeight()), ui.Atlas)
+	help.Color = colornames.White
+	for _, line := range chromaHelp {
+		fmt.Fprintln(help, line)
+	}
+	help.Draw(ctx.Win, pixel.IM)
+}
+
+func init() {
+	core.RegisterTest(&ChromaSubsampling{})
+}
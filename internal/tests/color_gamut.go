@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/ui"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+type ColorGamut struct {
+	modes  []string
+	mode   int
+	pic    *pixel.PictureData
+	picKey string // mode, size and brightness the cached picture was rendered for
+}
+
+// gamutHues are the primaries followed by the secondaries, in degrees.
+var gamutHues = []struct {
+	name string
+	hue  float64
+}{
+	{"Red", 0}, {"Green", 120}, {"Blue", 240},
+	{"Cyan", 180}, {"Magenta", 300}, {"Yellow", 60},
+}
+
+var gamutSaturations = []float64{0.25, 0.5, 0.75, 1}
+
+func (t *ColorGamut) Name() string { return "Color Gamut" }
+func (t *ColorGamut) Description() string {
+	return "Hue spectrum, hue wheel, C/M/Y ramps and saturation steps for clipping and hue shifts (Shift+Up/Down: pattern)"
+}
+func (t *ColorGamut) Order() int { return 25 }
+
+func (t *ColorGamut) Options() core.TestOptions {
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra: map[string]interface{}{
+			"mode": t.modes[t.mode],
+		},
+	}
+}
+
+func (t *ColorGamut) HandleKeys(ctx *core.WindowContext) {
+	if ctx.Win.Pressed(pixelgl.KeyLeftShift) || ctx.Win.Pressed(pixelgl.KeyRightShift) {
+		if ctx.Win.JustPressed(pixelgl.KeyUp) {
+			t.mode = (t.mode + 1) % len(t.modes)
+		}
+		if ctx.Win.JustPressed(pixelgl.KeyDown) {
+			t.mode = (t.mode - 1 + len(t.modes)) % len(t.modes)
+		}
+	} else {
+		core.AdjustBrightnessWithKeys(ctx, 0.1)
+	}
+}
+
+func (t *ColorGamut) SetOption(key, value string) error {
+	if key != "mode" {
+		return core.ErrUnknownOption
+	}
+	i, err := core.Choose(value, t.modes)
+	if err != nil {
+		return err
+	}
+	t.mode = i
+	return nil
+}
+
+func (t *ColorGamut) Settings() map[string]string {
+	return map[string]string{"mode": t.modes[t.mode]}
+}
+
+func (t *ColorGamut) Run(ctx *core.WindowContext) {
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+
+	bounds := ctx.Win.Bounds()
+	width := int(bounds.W())
+	height := int(bounds.H())
+
+	key := fmt.Sprintf("%s %dx%d %.2f", t.modes[t.mode], width, height, ctx.Brightness)
+	if t.pic == nil || t.picKey != key {
+		t.pic = pixel.MakePictureData(bounds)
+		t.picKey = key
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				c := t.colorAt(x, y, width, height)
+				t.pic.Pix[y*width+x] = core.AdjustBrightness(c, ctx.Brightness)
+			}
+		}
+	}
+
+	sprite := pixel.NewSprite(t.pic, bounds)
+	sprite.Draw(ctx.Win, pixel.IM.Moved(bounds.Center()))
+
+	if t.modes[t.mode] == "saturation steps" {
+		t.drawLabels(ctx, width, height)
+	}
+}
+
+// colorAt returns the pattern color for pixel (x, y); y grows upwards as in Pix.
+func (t *ColorGamut) colorAt(x, y, width, height int) color.RGBA {
+	fx := float64(x) / float64(width-1)
+	fy := float64(y) / float64(height-1)
+
+	switch t.modes[t.mode] {
+	case "spectrum":
+		// Upper half ramps saturation from white, lower half ramps value to black.
+		hue := fx * 360
+		if fy >= 0.5 {
+			return hsvToRGB(hue, (1-fy)*2, 1)
+		}
+		return hsvToRGB(hue, 1, fy*2)
+
+	case "hue wheel":
+		r := math.Min(float64(width), float64(height)) / 2 * 0.9
+		dx := float64(x) - float64(width)/2
+		dy := float64(y) - float64(height)/2
+		dist := math.Hypot(dx, dy)
+		if dist > r {
+			return colornames.Black
+		}
+		hue := math.Atan2(dy, dx) * 180 / math.Pi
+		if hue < 0 {
+			hue += 360
+		}
+		return hsvToRGB(hue, dist/r, 1)
+
+	case "secondary ramps":
+		band := 2 - y*3/height
+		v := uint8(fx * 255)
+		switch band {
+		case 0:
+			return color.RGBA{0, v, v, 255}
+		case 1:
+			return color.RGBA{v, 0, v, 255}
+		default:
+			return color.RGBA{v, v, 0, 255}
+		}
+
+	default: // saturation steps
+		row := len(gamutHues) - 1 - y*len(gamutHues)/height
+		col := x * len(gamutSaturations) / width
+		return hsvToRGB(gamutHues[row].hue, gamutSaturations[col], 1)
+	}
+}
+
+func (t *ColorGamut) drawLabels(ctx *core.WindowContext, width, height int) {
+	cellW := float64(width) / float64(len(gamutSaturations))
+	cellH := float64(height) / float64(len(gamutHues))
+
+	for row, h := range gamutHues {
+		for col, s := range gamutSaturations {
+			txt := text.New(pixel.V(float64(col)*cellW+10, float64(height)-float64(row)*cellH-20), ui.Atlas)
+			txt.Color = colornames.Black
+			fmt.Fprintf(txt, "%s %.0f%%", h.name, s*100)
+			txt.Draw(ctx.Win, pixel.IM)
+		}
+	}
+}
+
+// hsvToRGB converts hue in degrees, saturation and value in 0..1 to an opaque color.
+func hsvToRGB(h, s, v float64) color.RGBA {
+	c := v * s
+	hp := math.Mod(h/60, 6)
+	x := c * (1 - math.Abs(math.Mod(hp, 2)-1))
+
+	var r, g, b float64
+	switch {
+	case hp < 1:
+		r, g, b = c, x, 0
+	case hp < 2:
+		r, g, b = x, c, 0
+	case hp < 3:
+		r, g, b = 0, c, x
+	case hp < 4:
+		r, g, b = 0, x, c
+	case hp < 5:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	m := v - c
+	return color.RGBA{
+		R: uint8(math.Round((r + m) * 255)),
+		G: uint8(math.Round((g + m) * 255)),
+		B: uint8(math.Round((b + m) * 255)),
+		A: 255,
+	}
+}
+
+func init() {
+	core.RegisterTest(&ColorGamut{
+		modes: []string{"spectrum", "hue wheel", "secondary ramps", "saturation steps"},
+	})
+}
@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/keshon/screen-tester/internal/core"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+type ContrastPatterns struct {
+	mode        string // "ansi" or "window"
+	windowIndex int
+	levelIndex  int
+	surround    int
+}
+
+// contrastWindows are the window areas in percent of the screen area.
+var contrastWindows = []int{1, 2, 10, 18, 25, 50, 100}
+
+// contrastLevels are the window (or white square) levels in percent of full code value.
+var contrastLevels = []int{100, 90, 75, 50, 25, 10, 0}
+
+var contrastSurrounds = []struct {
+	name  string
+	color color.RGBA
+}{
+	{"black", color.RGBA{0, 0, 0, 255}},
+	{"gray", color.RGBA{128, 128, 128, 255}},
+	{"white", color.RGBA{255, 255, 255, 255}},
+}
+
+func (t *ContrastPatterns) Name() string { return "Contrast Patterns" }
+func (t *ContrastPatterns) Description() string {
+	return "ANSI 4x4 checkerboard and APL window patches for meter readings (M: mode, Shift+Up/Down: window size, L: level, B: surround)"
+}
+func (t *ContrastPatterns) Order() int { return 35 }
+
+func (t *ContrastPatterns) Options() core.TestOptions {
+	if t.mode == "" {
+		t.mode = "ansi"
+	}
+	extra := map[string]interface{}{
+		"mode":  t.mode,
+		"level": fmt.Sprintf("%d%%", contrastLevels[t.levelIndex]),
+	}
+	if t.mode == "window" {
+		extra["window"] = fmt.Sprintf("%d%%", contrastWindows[t.windowIndex])
+		extra["surround"] = contrastSurrounds[t.surround].name
+	}
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra:      extra,
+	}
+}
+
+func (t *ContrastPatterns) HandleKeys(ctx *core.WindowContext) {
+	win := ctx.Win
+
+	if win.Pressed(pixelgl.KeyLeftShift) || win.Pressed(pixelgl.KeyRightShift) {
+		if win.JustPressed(pixelgl.KeyUp) && t.windowIndex < len(contrastWindows)-1 {
+			t.windowIndex++
+		}
+		if win.JustPressed(pixelgl.KeyDown) && t.windowIndex > 0 {
+			t.windowIndex--
+		}
+	} else {
+		core.AdjustBrightnessWithKeys(ctx, 0.1)
+	}
+
+	if win.JustPressed(pixelgl.KeyM) {
+		if t.mode == "window" {
+			t.mode = "ansi"
+		} else {
+			t.mode = "window"
+		}
+	}
+	if win.JustPressed(pixelgl.KeyL) {
+		t.levelIndex = (t.levelIndex + 1) % len(contrastLevels)
+	}
+	if win.JustPressed(pixelgl.KeyB) {
+		t.surround = (t.surround + 1) % len(contrastSurrounds)
+	}
+}
+
+func (t *ContrastPatterns) SetOption(key, value string) error {
+	switch key {
+	case "mode":
+		i, err := core.Choose(value, []string{"ansi", "window"})
+		if err != nil {
+			return err
+		}
+		t.mode = []string{"ansi", "window"}[i]
+	case "window":
+		i, err := choosePercent(value, contrastWindows)
+		if err != nil {
+			return err
+		}
+		t.windowIndex = i
+	case "level":
+		i, err := choosePercent(value, contrastLevels)
+		if err != nil {
+			return err
+		}
+		t.levelIndex = i
+	case "surround":
+		for i, s := range contrastSurrounds {
+			if s.name == value {
+				t.surround = i
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of black, gray, white", value)
+	default:
+		return core.ErrUnknownOption
+	}
+	return nil
+}
+
+func (t *ContrastPatterns) Settings() map[string]string {
+	if t.mode == "" {
+		t.mode = "ansi"
+	}
+	return map[string]string{
+		"mode":     t.mode,
+		"level":    fmt.Sprintf("%d%%", contrastLevels[t.levelIndex]),
+		"window":   fmt.Sprintf("%d%%", contrastWindows[t.windowIndex]),
+		"surround": contrastSurrounds[t.surround].name,
+	}
+}
+
+// choosePercent returns the index of a percentage such as "18" or "18%" in choices.
+func choosePercent(value string, choices []int) (int, error) {
+	p, err := core.ParsePercent(value)
+	if err != nil {
+		return 0, err
+	}
+	for i, c := range choices {
+		if c == p {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%d%% is not one of %v", p, choices)
+}
+
+func (t *ContrastPatterns) Run(ctx *core.WindowContext) {
+	if t.mode == "" {
+		t.mode = "ansi"
+	}
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+
+	bounds := ctx.Win.Bounds()
+	width := bounds.W()
+	height := bounds.H()
+
+	v := uint8(255 * contrastLevels[t.levelIndex] / 100)
+	level := core.AdjustBrightness(color.RGBA{v, v, v, 255}, ctx.Brightness)
+
+	imd := imdraw.New(nil)
+
+	if t.mode == "ansi" {
+		ctx.Win.Clear(color.RGBA{0, 0, 0, 255})
+		imd.Color = level
+		for row := 0; row < 4; row++ {
+			for col := 0; col < 4; col++ {
+				if (row+col)%2 != 0 {
+					continue
+				}
+				// Integer edges keep every cell boundary on a pixel boundary.
+				x0 := math.Floor(width * float64(col) / 4)
+				x1 := math.Floor(width * float64(col+1) / 4)
+				y1 := height - math.Floor(height*float64(row)/4)
+				y0 := height - math.Floor(height*float64(row+1)/4)
+				imd.Push(pixel.V(x0, y0), pixel.V(x1, y1))
+				imd.Rectangle(0)
+			}
+		}
+		imd.Draw(ctx.Win)
+		return
+	}
+
+	ctx.Win.Clear(core.AdjustBrightness(contrastSurrounds[t.surround].color, ctx.Brightness))
+
+	// The window keeps the screen aspect ratio, so both sides scale by sqrt(area).
+	scale := math.Sqrt(float64(contrastWindows[t.windowIndex]) / 100)
+	w := math.Round(width * scale)
+	h := math.Round(height * scale)
+	x0 := math.Floor((width - w) / 2)
+	y0 := math.Floor((height - h) / 2)
+
+	imd.Color = level
+	imd.Push(pixel.V(x0, y0), pixel.V(x0+w, y0+h))
+	imd.Rectangle(0)
+	imd.Draw(ctx.Win)
+}
+
+func init() {
+	core.RegisterTest(&ContrastPatterns{})
+}
@@ -0,0 +1,210 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/keshon/screen-tester/internal/core"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+type Convergence struct {
+	layers       int
+	distinct     bool // each layer keeps its own color instead of mixing to white
+	cells        int
+	defaultCells int
+	minCells     int
+	maxCells     int
+	step         int
+}
+
+// convergenceLayers selects which single-channel layers are drawn. With all
+// three on, converged geometry is white and misaligned channels show as
+// colored fringes; the other sets isolate one channel or compare two.
+var convergenceLayers = []struct {
+	name     string
+	channels []color.RGBA
+}{
+	{"red+green+blue", []color.RGBA{{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255}}},
+	{"red", []color.RGBA{{255, 0, 0, 255}}},
+	{"green", []color.RGBA{{0, 255, 0, 255}}},
+	{"blue", []color.RGBA{{0, 0, 255, 255}}},
+	{"red+green", []color.RGBA{{255, 0, 0, 255}, {0, 255, 0, 255}}},
+	{"green+blue", []color.RGBA{{0, 255, 0, 255}, {0, 0, 255, 255}}},
+	{"red+blue", []color.RGBA{{255, 0, 0, 255}, {0, 0, 255, 255}}},
+}
+
+func (t *Convergence) Name() string { return "Convergence" }
+func (t *Convergence) Description() string {
+	return "Crosshairs and dots drawn as separate red, green and blue layers for projector and CRT alignment (M: channel layers, C: mixed/distinct layer colors, Shift+Up/Down: grid density)"
+}
+func (t *Convergence) Order() int { return 41 }
+
+func (t *Convergence) Options() core.TestOptions {
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra: map[string]interface{}{
+			"layers": convergenceLayers[t.layers].name,
+			"colors": t.colorMode(),
+			"grid":   fmt.Sprintf("%d columns", t.getCells()),
+		},
+	}
+}
+
+func (t *Convergence) HandleKeys(ctx *core.WindowContext) {
+	if ctx.Win.Pressed(pixelgl.KeyLeftShift) || ctx.Win.Pressed(pixelgl.KeyRightShift) {
+		if ctx.Win.JustPressed(pixelgl.KeyUp) {
+			t.setCells(t.getCells() + t.step)
+		}
+		if ctx.Win.JustPressed(pixelgl.KeyDown) {
+			t.setCells(t.getCells() - t.step)
+		}
+	} else {
+		core.AdjustBrightnessWithKeys(ctx, 0.1)
+	}
+
+	if ctx.Win.JustPressed(pixelgl.KeyM) {
+		t.layers = (t.layers + 1) % len(convergenceLayers)
+	}
+	if ctx.Win.JustPressed(pixelgl.KeyC) {
+		t.distinct = !t.distinct
+	}
+}
+
+func (t *Convergence) SetOption(key, value string) error {
+	switch key {
+	case "layers":
+		for i, l := range convergenceLayers {
+			if l.name == value {
+				t.layers = i
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not a layer set such as red+green+blue or green", value)
+	case "colors":
+		i, err := core.Choose(value, []string{"mixed", "distinct"})
+		if err != nil {
+			return err
+		}
+		t.distinct = i == 1
+	case "grid":
+		n, err := strconv.Atoi(strings.TrimSuffix(value, " columns"))
+		if err != nil {
+			return err
+		}
+		t.setCells(n)
+	default:
+		return core.ErrUnknownOption
+	}
+	return nil
+}
+
+func (t *Convergence) Settings() map[string]string {
+	return map[string]string{
+		"layers": convergenceLayers[t.layers].name,
+		"colors": t.colorMode(),
+		"grid":   fmt.Sprintf("%d columns", t.getCells()),
+	}
+}
+
+func (t *Convergence) Run(ctx *core.WindowContext) {
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+
+	ctx.Win.Clear(colornames.Black)
+
+	bounds := ctx.Win.Bounds()
+	cols := t.getCells()
+	rows := int(float64(cols)*bounds.H()/bounds.W() + 0.5)
+	if rows < 2 {
+		rows = 2
+	}
+	cellW := bounds.W() / float64(cols)
+	cellH := bounds.H() / float64(rows)
+	arm := float64(int(cellW / 4))
+
+	// Mixed layers are added, not painted over, so overlapping channels mix
+	// to white exactly where they converge. Distinct layers are painted in
+	// turn, so every line keeps the color of its channel and a misaligned
+	// layer shows as a separate line next to the one above it.
+	if !t.distinct {
+		ctx.Win.SetComposeMethod(pixel.ComposePlus)
+	}
+	for _, ch := range convergenceLayers[t.layers].channels {
+		imd := imdraw.New(nil)
+		imd.Color = core.AdjustBrightness(ch, ctx.Brightness)
+
+		for row := 0; row <= rows; row++ {
+			for col := 0; col <= cols; col++ {
+				x := snapPixel(float64(col)*cellW, bounds.W())
+				y := snapPixel(float64(row)*cellH, bounds.H())
+
+				imd.Push(pixel.V(x-arm, y), pixel.V(x+arm+1, y+1))
+				imd.Rectangle(0)
+				imd.Push(pixel.V(x, y-arm), pixel.V(x+1, y))
+				imd.Rectangle(0)
+				imd.Push(pixel.V(x, y+1), pixel.V(x+1, y+arm+1))
+				imd.Rectangle(0)
+
+				if row < rows && col < cols {
+					cx := float64(int(float64(col)*cellW + cellW/2))
+					cy := float64(int(float64(row)*cellH + cellH/2))
+					imd.Push(pixel.V(cx, cy), pixel.V(cx+2, cy+2))
+					imd.Rectangle(0)
+				}
+			}
+		}
+		imd.Draw(ctx.Win)
+	}
+	ctx.Win.SetComposeMethod(pixel.ComposeOver)
+}
+
+// snapPixel rounds v down to a pixel column or row, keeping the last grid
+// line inside the screen.
+func snapPixel(v, limit float64) float64 {
+	v = float64(int(v))
+	if v > limit-1 {
+		v = limit - 1
+	}
+	return v
+}
+
+func (t *Convergence) colorMode() string {
+	if t.distinct {
+		return "distinct"
+	}
+	return "mixed"
+}
+
+func (t *Convergence) getCells() int {
+	if t.cells == 0 {
+		t.cells = t.defaultCells
+	}
+	return t.cells
+}
+
+func (t *Convergence) setCells(cells int) {
+	if cells < t.minCells {
+		cells = t.minCells
+	}
+	if cells > t.maxCells {
+		cells = t.maxCells
+	}
+	t.cells = cells
+}
+
+func init() {
+	core.RegisterTest(&Convergence{
+		defaultCells: 16,
+		minCells:     4,
+		maxCells:     64,
+		step:         4,
+	})
+}
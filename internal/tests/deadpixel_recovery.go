@@ -56,7 +56,9 @@ func (t *DeadPixelRecovery) HandleKeys(ctx *core.WindowContext) {
 }
 
 func (t *DeadPixelRecovery) Run(ctx *core.WindowContext) {
-	t.HandleKeys(ctx)
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
 
 	if t.state == nil {
 		t.state = &flickerState{
@@ -90,6 +92,22 @@ func (t *DeadPixelRecovery) Run(ctx *core.WindowContext) {
 	sprite.Draw(ctx.Win, pixel.IM.Moved(bounds.Center()))
 }
 
+func (t *DeadPixelRecovery) SetOption(key, value string) error {
+	if key != "speed" {
+		return core.ErrUnknownOption
+	}
+	d, err := core.ParseDurationMs(value)
+	if err != nil {
+		return err
+	}
+	t.setSpeed(d)
+	return nil
+}
+
+func (t *DeadPixelRecovery) Settings() map[string]string {
+	return map[string]string{"speed": t.getSpeed().String()}
+}
+
 func (t *DeadPixelRecovery) getSpeed() time.Duration {
 	if t.speed == 0 {
 		t.speed = t.defaultSpeed
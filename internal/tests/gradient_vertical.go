@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"fmt"
 	"image/color"
 
 	"github.com/keshon/screen-tester/internal/core"
@@ -49,7 +50,9 @@ func (t *GradientVertical) HandleKeys(ctx *core.WindowContext) {
 }
 
 func (t *GradientVertical) Run(ctx *core.WindowContext) {
-	t.HandleKeys(ctx)
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
 
 	bounds := ctx.Win.Bounds()
 	width := int(bounds.W())
@@ -72,6 +75,21 @@ func (t *GradientVertical) Run(ctx *core.WindowContext) {
 	sprite.Draw(ctx.Win, pixel.IM.Moved(bounds.Center()))
 }
 
+func (t *GradientVertical) SetOption(key, value string) error {
+	if key != "direction" {
+		return core.ErrUnknownOption
+	}
+	if value != "black to white" && value != "white to black" {
+		return fmt.Errorf("%q is not one of black to white, white to black", value)
+	}
+	t.setDirection(value)
+	return nil
+}
+
+func (t *GradientVertical) Settings() map[string]string {
+	return map[string]string{"direction": t.getDirection()}
+}
+
 func (t *GradientVertical) getDirection() string {
 	if t.opts.Extra == nil {
 		t.opts.Extra = map[string]interface{}{}
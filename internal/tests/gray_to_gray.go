@@ -0,0 +1,266 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/ui"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+type GrayToGray struct {
+	mode       string // "switch" or "moving bar"
+	levelSet   int
+	levels     []uint8 // a preset from grayLevelSets or a custom list
+	interval   int     // frames between switches
+	barStep    int     // bar movement in pixels per frame
+	frame      int
+	minFrames  int
+	maxFrames  int
+	minBarStep int
+	maxBarStep int
+}
+
+var grayLevelSets = [][]uint8{
+	{0, 64, 128, 192, 255},
+	{0, 51, 102, 153, 204, 255},
+	{0, 128, 255},
+	{16, 64, 128, 192, 235},
+}
+
+const (
+	grayLabelHeight = 18
+	grayMaxLevels   = 16
+)
+
+func (t *GrayToGray) Name() string { return "Gray to Gray" }
+func (t *GrayToGray) Description() string {
+	return "Patches switching between every pair of gray levels to show overdrive overshoot and inverse ghosting (M: switch/moving bar, Shift+Up/Down: frames per switch or bar speed, L: level set; the levels option also takes a list such as 0,64,128,255)"
+}
+func (t *GrayToGray) Order() int { return 54 }
+
+func (t *GrayToGray) Options() core.TestOptions {
+	t.ensureDefaults()
+	extra := map[string]interface{}{
+		"mode":   t.mode,
+		"levels": levelsString(t.levels),
+	}
+	if t.mode == "switch" {
+		extra["interval"] = fmt.Sprintf("%d frames", t.interval)
+	} else {
+		extra["bar"] = fmt.Sprintf("%d px/frame", t.barStep)
+	}
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra:      extra,
+	}
+}
+
+func (t *GrayToGray) HandleKeys(ctx *core.WindowContext) {
+	win := ctx.Win
+
+	if win.Pressed(pixelgl.KeyLeftShift) || win.Pressed(pixelgl.KeyRightShift) {
+		delta := 0
+		if win.JustPressed(pixelgl.KeyUp) {
+			delta = 1
+		}
+		if win.JustPressed(pixelgl.KeyDown) {
+			delta = -1
+		}
+		if t.mode == "switch" {
+			t.interval = clampInt(t.interval+delta, t.minFrames, t.maxFrames)
+		} else {
+			t.barStep = clampInt(t.barStep+delta, t.minBarStep, t.maxBarStep)
+		}
+	} else {
+		core.AdjustBrightnessWithKeys(ctx, 0.1)
+	}
+
+	if win.JustPressed(pixelgl.KeyM) {
+		if t.mode == "switch" {
+			t.mode = "moving bar"
+		} else {
+			t.mode = "switch"
+		}
+	}
+	if win.JustPressed(pixelgl.KeyL) {
+		t.levelSet = (t.levelSet + 1) % len(grayLevelSets)
+		t.levels = grayLevelSets[t.levelSet]
+	}
+}
+
+func (t *GrayToGray) SetOption(key, value string) error {
+	switch key {
+	case "mode":
+		i, err := core.Choose(value, []string{"switch", "moving bar"})
+		if err != nil {
+			return err
+		}
+		t.mode = []string{"switch", "moving bar"}[i]
+	case "levels":
+		levels, err := parseLevels(value)
+		if err != nil {
+			return err
+		}
+		t.levels = levels
+		for i, set := range grayLevelSets {
+			if levelsString(set) == levelsString(levels) {
+				t.levelSet = i
+			}
+		}
+	case "interval":
+		n, err := strconv.Atoi(strings.TrimSuffix(value, " frames"))
+		if err != nil {
+			return err
+		}
+		t.interval = clampInt(n, t.minFrames, t.maxFrames)
+	case "bar":
+		n, err := strconv.Atoi(strings.TrimSuffix(value, " px/frame"))
+		if err != nil {
+			return err
+		}
+		t.barStep = clampInt(n, t.minBarStep, t.maxBarStep)
+	default:
+		return core.ErrUnknownOption
+	}
+	return nil
+}
+
+func (t *GrayToGray) Settings() map[string]string {
+	t.ensureDefaults()
+	return map[string]string{
+		"mode":     t.mode,
+		"levels":   levelsString(t.levels),
+		"interval": fmt.Sprintf("%d frames", t.interval),
+		"bar":      fmt.Sprintf("%d px/frame", t.barStep),
+	}
+}
+
+func (t *GrayToGray) Run(ctx *core.WindowContext) {
+	t.ensureDefaults()
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+	t.frame++
+
+	ctx.Win.Clear(colornames.Black)
+
+	bounds := ctx.Win.Bounds()
+	levels := t.levels
+	n := len(levels)
+	cellW := float64(int(bounds.W() / float64(n)))
+	cellH := float64(int(bounds.H() / float64(n)))
+	patchH := cellH - grayLabelHeight
+
+	// In switch mode every patch flips at the same moment, so all
+	// transitions are visible side by side on a single frame.
+	atEnd := (t.frame/t.interval)%2 == 1
+
+	imd := imdraw.New(nil)
+	labels := text.New(pixel.ZV, ui.Atlas)
+	labels.Color = colornames.White
+
+	for row, from := range levels {
+		for col, to := range levels {
+			x0 := float64(col) * cellW
+			y0 := bounds.H() - float64(row+1)*cellH
+
+			fromCol := core.AdjustBrightness(color.RGBA{from, from, from, 255}, ctx.Brightness)
+			toCol := core.AdjustBrightness(color.RGBA{to, to, to, 255}, ctx.Brightness)
+
+			if t.mode == "switch" {
+				imd.Color = fromCol
+				if atEnd {
+					imd.Color = toCol
+				}
+				imd.Push(pixel.V(x0+1, y0+1), pixel.V(x0+cellW-1, y0+patchH))
+				imd.Rectangle(0)
+			} else {
+				// The start level is the background and a bar at the end
+				// level sweeps across it, so each edge is one transition.
+				imd.Color = fromCol
+				imd.Push(pixel.V(x0+1, y0+1), pixel.V(x0+cellW-1, y0+patchH))
+				imd.Rectangle(0)
+
+				barW := float64(int(cellW / 6))
+				span := int(cellW - 2 - barW)
+				if span > 0 {
+					bx := x0 + 1 + float64((t.frame*t.barStep)%span)
+					imd.Color = toCol
+					imd.Push(pixel.V(bx, y0+1), pixel.V(bx+barW, y0+patchH))
+					imd.Rectangle(0)
+				}
+			}
+
+			labels.Dot = pixel.V(x0+4, y0+patchH+4)
+			fmt.Fprintf(labels, "%d->%d", from, to)
+		}
+	}
+
+	imd.Draw(ctx.Win)
+	labels.Draw(ctx.Win, pixel.IM)
+}
+
+func (t *GrayToGray) ensureDefaults() {
+	if t.mode == "" {
+		t.mode = "switch"
+	}
+	if t.levels == nil {
+		t.levels = grayLevelSets[t.levelSet]
+	}
+}
+
+// parseLevels reads a list of gray levels separated by slashes, as they
+// are reported, or by commas, e.g. 0,64,128,255.
+func parseLevels(value string) ([]uint8, error) {
+	parts := strings.FieldsFunc(value, func(r rune) bool { return r == '/' || r == ',' })
+	if len(parts) < 2 || len(parts) > grayMaxLevels {
+		return nil, fmt.Errorf("%q is not a list of 2 to %d gray levels such as 0,64,128,255", value, grayMaxLevels)
+	}
+	levels := make([]uint8, len(parts))
+	for i, p := range parts {
+		l, err := strconv.ParseUint(strings.TrimSpace(p), 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("gray level %q is not a number from 0 to 255", p)
+		}
+		levels[i] = uint8(l)
+	}
+	return levels, nil
+}
+
+func levelsString(levels []uint8) string {
+	parts := make([]string, len(levels))
+	for i, l := range levels {
+		parts[i] = fmt.Sprint(l)
+	}
+	return strings.Join(parts, "/")
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+func init() {
+	core.RegisterTest(&GrayToGray{
+		interval:   30,
+		barStep:    8,
+		minFrames:  1,
+		maxFrames:  120,
+		minBarStep: 1,
+		maxBarStep: 32,
+	})
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/input"
+	"github.com/keshon/screen-tester/internal/ui"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+type InputLatency struct {
+	state      *latencyState
+	flashHold  int // frames the marker stays lit
+	maxSamples int
+}
+
+type latencyState struct {
+	start     time.Time
+	flashLeft int
+	polledAt  time.Time // when the triggering input was polled
+	awaiting  bool      // the flash frame was drawn but its swap time is not known yet
+	samples   []time.Duration
+}
+
+func (t *InputLatency) Name() string { return "Input Latency" }
+func (t *InputLatency) Description() string {
+	return "Flashes the marker on the first frame after Space, Enter or a mouse click and measures input-to-swap time; film the screen and input device with a high-speed camera for display latency (R: reset)"
+}
+func (t *InputLatency) Order() int { return 56 }
+
+func (t *InputLatency) Options() core.TestOptions {
+	t.ensureState()
+	extra := map[string]interface{}{
+		"samples": len(t.state.samples),
+	}
+	if n := len(t.state.samples); n > 0 {
+		extra["last"] = fmt.Sprintf("%.1f ms", ui.Millis(t.state.samples[n-1]))
+	}
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra:      extra,
+	}
+}
+
+func (t *InputLatency) HandleKeys(ctx *core.WindowContext) {
+	core.AdjustBrightnessWithKeys(ctx, 0.1)
+
+	if ctx.Win.JustPressed(pixelgl.KeyR) {
+		t.state.samples = nil
+		t.state.start = time.Now()
+	}
+
+	if input.JustPressedAny(ctx.Win, input.LatencyTriggers...) {
+		// pixelgl polled this input in the previous win.Update, just before
+		// the frame clock ticked, so that tick stands for the poll time
+		// rather than now. The flash goes out with this frame.
+		t.state.polledAt = ctx.Frames.Last()
+		t.state.flashLeft = t.flashHold
+		t.state.awaiting = true
+	}
+}
+
+func (t *InputLatency) Run(ctx *core.WindowContext) {
+	t.ensureState()
+
+	// The last frame timestamp is now the swap that presented the flash,
+	// so the sample spans the rest of that frame plus the swap itself.
+	if t.state.awaiting && t.state.flashLeft < t.flashHold && !t.state.polledAt.IsZero() {
+		t.state.samples = append(t.state.samples, ctx.Frames.Last().Sub(t.state.polledAt))
+		if len(t.state.samples) > t.maxSamples {
+			t.state.samples = t.state.samples[1:]
+		}
+		t.state.awaiting = false
+	}
+
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+
+	ctx.Win.Clear(colornames.Black)
+	bounds := ctx.Win.Bounds()
+
+	marker := float64(int(bounds.H() / 3))
+	imd := imdraw.New(nil)
+	imd.Color = color.RGBA{32, 32, 32, 255}
+	if t.state.flashLeft > 0 {
+		imd.Color = core.AdjustBrightness(color.RGBA{255, 255, 255, 255}, ctx.Brightness)
+		t.state.flashLeft--
+	}
+	imd.Push(pixel.V(0, bounds.H()-marker), pixel.V(marker, bounds.H()))
+	imd.Rectangle(0)
+	imd.Draw(ctx.Win)
+
+	// The counter changes every frame so each camera frame can be matched
+	// to the app clock.
+	counter := text.New(pixel.ZV, ui.Atlas)
+	counter.Color = colornames.White
+	fmt.Fprintf(counter, "%07.1f ms", ui.Millis(time.Since(t.state.start)))
+	scale := float64(int(bounds.W() / 3 / counter.Bounds().W()))
+	if scale < 1 {
+		scale = 1
+	}
+	pos := pixel.V(float64(int(bounds.W()/2-counter.Bounds().W()*scale/2)), float64(int(bounds.H()/2)))
+	counter.Draw(ctx.Win, pixel.IM.Scaled(pixel.ZV, scale).Moved(pos))
+
+	summary := text.New(pixel.ZV, ui.Atlas)
+	summary.Color = colornames.White
+	fmt.Fprint(summary, t.summary())
+	pos = pixel.V(float64(int(bounds.W()/2-summary.Bounds().W())), float64(int(bounds.H()/2-60)))
+	summary.Draw(ctx.Win, pixel.IM.Scaled(pixel.ZV, 2).Moved(pos))
+}
+
+func (t *InputLatency) summary() string {
+	n := len(t.state.samples)
+	if n == 0 {
+		return "Press Space, Enter or click to measure"
+	}
+	min, max, total := t.state.samples[0], t.state.samples[0], time.Duration(0)
+	for _, d := range t.state.samples {
+		if d < min {
+			min = d
+		}
+		if d > max {
+			max = d
+		}
+		total += d
+	}
+	return fmt.Sprintf("Input to swap: last %.1f ms  avg %.1f  min %.1f  max %.1f  (%d samples)",
+		ui.Millis(t.state.samples[n-1]), ui.Millis(total/time.Duration(n)), ui.Millis(min), ui.Millis(max), n)
+}
+
+func (t *InputLatency) ensureState() {
+	if t.state != nil {
+		return
+	}
+	t.state = &latencyState{start: time.Now()}
+}
+
+func init() {
+	core.RegisterTest(&InputLatency{
+		flashHold:  6,
+		maxSamples: 50,
+	})
+}
@@ -0,0 +1,264 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"time"
+
+	"github.com/keshon/screen-tester/internal/core"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+type LocalDimming struct {
+	state       *dimmingState
+	defaultSize int
+	minSize     int
+	maxSize     int
+	step        int
+	sweepSpeed  float64 // pixels per second
+}
+
+type dimmingState struct {
+	lastUpdate time.Time
+	size       int
+	shape      string // "box" or "circle"
+	pos        pixel.Vec
+	pinned     []pixel.Vec
+	sweep      bool
+	sweepDir   float64
+	zoneIndex  int
+	imd        *imdraw.IMDraw
+}
+
+type zoneGrid struct {
+	cols int
+	rows int
+}
+
+// dimmingZones are typical full-array local dimming layouts; index 0 hides the grid.
+var dimmingZones = []zoneGrid{{0, 0}, {8, 4}, {16, 9}, {24, 14}, {32, 18}, {48, 27}, {64, 36}}
+
+func (t *LocalDimming) Name() string { return "Local Dimming" }
+func (t *LocalDimming) Description() string {
+	return "White highlight on black for blooming checks (Mouse: move, Wheel or Shift+Up/Down: size, S: shape, A: auto sweep, Z: zone grid, Click: pin copy, Right click: clear pins)"
+}
+func (t *LocalDimming) Order() int { return 45 }
+
+func (t *LocalDimming) Options() core.TestOptions {
+	t.ensureState()
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra: map[string]interface{}{
+			"size":   t.state.size,
+			"shape":  t.state.shape,
+			"sweep":  t.state.sweep,
+			"zones":  dimmingZones[t.state.zoneIndex].String(),
+			"pinned": len(t.state.pinned),
+		},
+	}
+}
+
+func (t *LocalDimming) HandleKeys(ctx *core.WindowContext) {
+	win := ctx.Win
+
+	if win.Pressed(pixelgl.KeyLeftShift) || win.Pressed(pixelgl.KeyRightShift) {
+		if win.JustPressed(pixelgl.KeyUp) {
+			t.setSize(t.state.size + t.step)
+		}
+		if win.JustPressed(pixelgl.KeyDown) {
+			t.setSize(t.state.size - t.step)
+		}
+	} else {
+		core.AdjustBrightnessWithKeys(ctx, 0.1)
+	}
+
+	if scroll := win.MouseScroll(); scroll.Y != 0 {
+		t.setSize(t.state.size + int(scroll.Y)*t.step)
+	}
+
+	if win.JustPressed(pixelgl.KeyS) {
+		if t.state.shape == "box" {
+			t.state.shape = "circle"
+		} else {
+			t.state.shape = "box"
+		}
+	}
+	if win.JustPressed(pixelgl.KeyA) {
+		t.state.sweep = !t.state.sweep
+	}
+	if win.JustPressed(pixelgl.KeyZ) {
+		t.state.zoneIndex = (t.state.zoneIndex + 1) % len(dimmingZones)
+	}
+
+	if !t.state.sweep && win.MousePosition() != win.MousePreviousPosition() {
+		t.state.pos = win.MousePosition()
+	}
+	if win.JustPressed(pixelgl.MouseButtonLeft) {
+		t.state.pinned = append(t.state.pinned, t.state.pos)
+	}
+	if win.JustPressed(pixelgl.MouseButtonRight) {
+		t.state.pinned = nil
+	}
+}
+
+func (t *LocalDimming) Run(ctx *core.WindowContext) {
+	t.ensureState()
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+
+	now := time.Now()
+	dt := now.Sub(t.state.lastUpdate).Seconds()
+	t.state.lastUpdate = now
+
+	bounds := ctx.Win.Bounds()
+	if t.state.pos == (pixel.Vec{}) {
+		t.state.pos = bounds.Center()
+	}
+	if t.state.sweep {
+		t.advanceSweep(dt, bounds)
+	}
+
+	ctx.Win.Clear(color.RGBA{0, 0, 0, 255})
+
+	imd := t.state.imd
+	imd.Clear()
+
+	if zones := dimmingZones[t.state.zoneIndex]; zones.cols > 0 {
+		imd.Color = color.RGBA{48, 48, 48, 255}
+		for i := 1; i < zones.cols; i++ {
+			x := bounds.W() * float64(i) / float64(zones.cols)
+			imd.Push(pixel.V(x, 0), pixel.V(x, bounds.H()))
+			imd.Line(1)
+		}
+		for i := 1; i < zones.rows; i++ {
+			y := bounds.H() * float64(i) / float64(zones.rows)
+			imd.Push(pixel.V(0, y), pixel.V(bounds.W(), y))
+			imd.Line(1)
+		}
+	}
+
+	imd.Color = core.AdjustBrightness(color.RGBA{255, 255, 255, 255}, ctx.Brightness)
+	for _, p := range t.state.pinned {
+		t.drawHighlight(imd, p)
+	}
+	t.drawHighlight(imd, t.state.pos)
+
+	imd.Draw(ctx.Win)
+}
+
+func (t *LocalDimming) SetOption(key, value string) error {
+	t.ensureState()
+	switch key {
+	case "size":
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		t.setSize(size)
+	case "shape":
+		i, err := core.Choose(value, []string{"box", "circle"})
+		if err != nil {
+			return err
+		}
+		t.state.shape = []string{"box", "circle"}[i]
+	case "sweep":
+		on, err := core.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		t.state.sweep = on
+	case "zones":
+		for i, z := range dimmingZones {
+			if z.String() == value {
+				t.state.zoneIndex = i
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not off or one of the zone layouts, e.g. 16x9", value)
+	default:
+		return core.ErrUnknownOption
+	}
+	return nil
+}
+
+func (t *LocalDimming) Settings() map[string]string {
+	t.ensureState()
+	return map[string]string{
+		"size":  strconv.Itoa(t.state.size),
+		"shape": t.state.shape,
+		"sweep": strconv.FormatBool(t.state.sweep),
+		"zones": dimmingZones[t.state.zoneIndex].String(),
+	}
+}
+
+func (t *LocalDimming) drawHighlight(imd *imdraw.IMDraw, center pixel.Vec) {
+	half := float64(t.state.size) / 2
+	if t.state.shape == "circle" {
+		imd.Push(center)
+		imd.Circle(half, 0)
+		return
+	}
+	imd.Push(center.Sub(pixel.V(half, half)), center.Add(pixel.V(half, half)))
+	imd.Rectangle(0)
+}
+
+// advanceSweep moves the highlight in a raster pattern: left to right, one
+// highlight height down, right to left, and back to the top after the last row.
+func (t *LocalDimming) advanceSweep(dt float64, bounds pixel.Rect) {
+	half := float64(t.state.size) / 2
+	t.state.pos.X += t.state.sweepDir * t.sweepSpeed * dt
+
+	if t.state.pos.X+half > bounds.W() || t.state.pos.X-half < 0 {
+		t.state.pos.X = core.Clamp(t.state.pos.X, half, bounds.W()-half)
+		t.state.sweepDir = -t.state.sweepDir
+		t.state.pos.Y -= float64(t.state.size)
+		if t.state.pos.Y-half < 0 {
+			t.state.pos.Y = bounds.H() - half
+		}
+	}
+}
+
+func (t *LocalDimming) ensureState() {
+	if t.state != nil {
+		return
+	}
+	t.state = &dimmingState{
+		lastUpdate: time.Now(),
+		size:       t.defaultSize,
+		shape:      "box",
+		sweepDir:   1,
+		imd:        imdraw.New(nil),
+	}
+}
+
+func (z zoneGrid) String() string {
+	if z.cols == 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%dx%d", z.cols, z.rows)
+}
+
+func (t *LocalDimming) setSize(size int) {
+	if size < t.minSize {
+		size = t.minSize
+	}
+	if size > t.maxSize {
+		size = t.maxSize
+	}
+	t.state.size = size
+}
+
+func init() {
+	core.RegisterTest(&LocalDimming{
+		defaultSize: 100,
+		minSize:     10,
+		maxSize:     800,
+		step:        10,
+		sweepSpeed:  300,
+	})
+}
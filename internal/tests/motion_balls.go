@@ -85,7 +85,9 @@ func (t *MotionBalls) HandleKeys(ctx *core.WindowContext) {
 }
 
 func (t *MotionBalls) Run(ctx *core.WindowContext) {
-	t.HandleKeys(ctx)
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
 
 	if t.state == nil {
 		t.init(ctx)
@@ -182,6 +184,25 @@ func (t *MotionBalls) moveAndBounce(b *ball, dt float64, bounds pixel.Rect) {
 	}
 }
 
+func (t *MotionBalls) SetOption(key, value string) error {
+	if key != "speed" {
+		return core.ErrUnknownOption
+	}
+	d, err := core.ParseDurationMs(value)
+	if err != nil {
+		return err
+	}
+	t.setSpeed(d)
+	if t.state != nil {
+		t.rescaleVelocities()
+	}
+	return nil
+}
+
+func (t *MotionBalls) Settings() map[string]string {
+	return map[string]string{"speed": t.getSpeed().String()}
+}
+
 func (t *MotionBalls) getSpeed() time.Duration {
 	if t.speed == 0 {
 		t.speed = t.defaultSpeed
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"image/color"
+	"strconv"
 
 	"github.com/keshon/screen-tester/internal/core"
 
@@ -53,7 +54,9 @@ func (t *PixelGrid) HandleKeys(ctx *core.WindowContext) {
 }
 
 func (t *PixelGrid) Run(ctx *core.WindowContext) {
-	t.HandleKeys(ctx)
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
 
 	size := t.getSize()
 	ctx.Win.Clear(core.AdjustBrightness(color.RGBA{0, 0, 0, 255}, ctx.Brightness)) // clear to black
@@ -73,6 +76,23 @@ func (t *PixelGrid) Run(ctx *core.WindowContext) {
 	imd.Draw(ctx.Win)
 }
 
+func (t *PixelGrid) SetOption(key, value string) error {
+	if key != "size" {
+		return core.ErrUnknownOption
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	t.getSize()
+	t.setSize(size)
+	return nil
+}
+
+func (t *PixelGrid) Settings() map[string]string {
+	return map[string]string{"size": strconv.Itoa(t.getSize())}
+}
+
 func (t *PixelGrid) getSize() int {
 	if t.opts.Extra == nil {
 		t.opts.Extra = map[string]interface{}{}
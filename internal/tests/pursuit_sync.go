@@ -0,0 +1,208 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/ui"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+type PursuitSync struct {
+	state           *pursuitState
+	defaultVelocity int
+	minVelocity     int
+	maxVelocity     int
+	velocityStep    int
+}
+
+type pursuitState struct {
+	velocity int     // target speed in pixels per second
+	step     int     // pixels per frame, the velocity rounded at the frame rate
+	hz       float64 // refresh rate the step was matched to
+	frame    int
+	x        int
+	imd      *imdraw.IMDraw
+}
+
+const (
+	pursuitObjectSize = 96
+	pursuitTickGap    = 16
+	pursuitMinSamples = 60
+)
+
+func (t *PursuitSync) Name() string { return "Pursuit Sync Track" }
+func (t *PursuitSync) Description() string {
+	return "Moving object and alternating sync track for pursuit-camera photos; top and bottom ticks line up in a correctly tracked photo. The speed is rounded to whole pixels per frame at the display refresh rate (Shift+Up/Down: px/s)"
+}
+func (t *PursuitSync) Order() int { return 53 }
+
+func (t *PursuitSync) Options() core.TestOptions {
+	t.ensureState()
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra: map[string]interface{}{
+			"velocity": fmt.Sprintf("%d px/s", t.state.velocity),
+			"step":     fmt.Sprintf("%d px/frame at %.1f Hz (%.0f px/s)", t.state.step, t.state.hz, float64(t.state.step)*t.state.hz),
+		},
+	}
+}
+
+func (t *PursuitSync) HandleKeys(ctx *core.WindowContext) {
+	if ctx.Win.Pressed(pixelgl.KeyLeftShift) || ctx.Win.Pressed(pixelgl.KeyRightShift) {
+		if ctx.Win.JustPressed(pixelgl.KeyUp) {
+			t.setVelocity(t.state.velocity + t.velocityStep)
+		}
+		if ctx.Win.JustPressed(pixelgl.KeyDown) {
+			t.setVelocity(t.state.velocity - t.velocityStep)
+		}
+	} else {
+		core.AdjustBrightnessWithKeys(ctx, 0.1)
+	}
+}
+
+func (t *PursuitSync) SetOption(key, value string) error {
+	if key != "velocity" {
+		return core.ErrUnknownOption
+	}
+	n, err := strconv.Atoi(strings.SplitN(value, " ", 2)[0]) // also accepts the "N px/s" it reports
+	if err != nil {
+		return err
+	}
+	t.ensureState()
+	t.setVelocity(n)
+	return nil
+}
+
+func (t *PursuitSync) Settings() map[string]string {
+	t.ensureState()
+	return map[string]string{
+		"velocity": strconv.Itoa(t.state.velocity),
+	}
+}
+
+func (t *PursuitSync) Run(ctx *core.WindowContext) {
+	t.ensureState()
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+	// The step follows the nominal rate rather than the measured one, which
+	// jitters from frame to frame.
+	if hz := ctx.Frames.Nominal; hz > 0 && hz != t.state.hz {
+		t.matchStep(hz)
+	}
+
+	bounds := ctx.Win.Bounds()
+	width := int(bounds.W())
+	midY := float64(int(bounds.H() / 2))
+
+	t.state.frame++
+	t.state.x = (t.state.x + t.state.step) % (width + pursuitObjectSize)
+
+	ctx.Win.Clear(core.AdjustBrightness(color.RGBA{128, 128, 128, 255}, ctx.Brightness))
+
+	imd := t.state.imd
+	imd.Clear()
+
+	// Sync track: ticks travel with the object. Even frames light the upper
+	// row and odd frames the lower one, so a photo spanning several frames
+	// shows both rows aligned only when the camera tracks the exact speed.
+	white := core.AdjustBrightness(color.RGBA{255, 255, 255, 255}, ctx.Brightness)
+	trackY := midY + pursuitObjectSize
+	rowY := trackY + 12
+	if t.state.frame%2 == 1 {
+		rowY = trackY - 12
+	}
+	imd.Color = colornames.Black
+	imd.Push(pixel.V(0, trackY-24), pixel.V(bounds.W(), trackY+24))
+	imd.Rectangle(0)
+	imd.Color = white
+	for x := t.state.x % pursuitTickGap; x < width; x += pursuitTickGap {
+		imd.Push(pixel.V(float64(x), rowY-8), pixel.V(float64(x+2), rowY+8))
+		imd.Rectangle(0)
+	}
+
+	// Test object: a framed square with 1, 2 and 4 pixel bars.
+	left := float64(t.state.x - pursuitObjectSize)
+	bottom := midY - pursuitObjectSize/2
+	imd.Color = colornames.Black
+	imd.Push(pixel.V(left, bottom), pixel.V(left+pursuitObjectSize, bottom+pursuitObjectSize))
+	imd.Rectangle(0)
+	imd.Color = white
+	for i, bar := range []int{1, 2, 4} {
+		y0 := bottom + 8 + float64(i)*28
+		for x := 8; x+bar <= pursuitObjectSize-8; x += bar * 2 {
+			imd.Push(pixel.V(left+float64(x), y0), pixel.V(left+float64(x+bar), y0+24))
+			imd.Rectangle(0)
+		}
+	}
+
+	imd.Draw(ctx.Win)
+
+	t.drawSyncIndicator(ctx, midY-pursuitObjectSize-40)
+}
+
+// drawSyncIndicator compares measured frame intervals with the monitor's
+// refresh period. Any interval far from one period means the track moved by
+// a different distance than the camera expects and the photo is invalid.
+func (t *PursuitSync) drawSyncIndicator(ctx *core.WindowContext, y float64) {
+	stats := ctx.Frames.Stats()
+	nominal := ctx.Frames.Nominal
+	period := ctx.Frames.Period()
+
+	status, col := "IN SYNC", colornames.Lime
+	switch {
+	case stats.Samples < pursuitMinSamples:
+		status, col = "MEASURING", colornames.Yellow
+	case stats.Dropped > 0 || stats.Min < period/2 || math.Abs(stats.MeasuredHz-nominal)/nominal > 0.02:
+		status, col = "OUT OF SYNC", colornames.Red
+	}
+
+	txt := text.New(pixel.ZV, ui.Atlas)
+	txt.Color = col
+	fmt.Fprintf(txt, "%s  %d px/frame = %.0f px/s  measured %.1f Hz / display %.0f Hz  dropped frames: %d in %d intervals",
+		status, t.state.step, float64(t.state.step)*t.state.hz, stats.MeasuredHz, nominal, stats.Dropped, stats.Samples)
+	x := float64(int((ctx.Win.Bounds().W() - txt.Bounds().W()*2) / 2))
+	txt.Draw(ctx.Win, pixel.IM.Scaled(pixel.ZV, 2).Moved(pixel.V(x, y)))
+}
+
+func (t *PursuitSync) ensureState() {
+	if t.state != nil {
+		return
+	}
+	t.state = &pursuitState{
+		velocity: t.defaultVelocity,
+		imd:      imdraw.New(nil),
+	}
+	t.matchStep(refreshRate())
+}
+
+func (t *PursuitSync) setVelocity(velocity int) {
+	t.state.velocity = clampInt(velocity, t.minVelocity, t.maxVelocity)
+	t.matchStep(t.state.hz)
+}
+
+// matchStep rounds the velocity to whole pixels per frame at hz, so every
+// frame moves the track by exactly the same distance.
+func (t *PursuitSync) matchStep(hz float64) {
+	t.state.hz = hz
+	t.state.step = max(1, int(math.Round(float64(t.state.velocity)/hz)))
+}
+
+func init() {
+	core.RegisterTest(&PursuitSync{
+		defaultVelocity: 960,
+		minVelocity:     120,
+		maxVelocity:     3840,
+		velocityStep:    120,
+	})
+}
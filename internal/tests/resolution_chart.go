@@ -0,0 +1,221 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/ui"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+type ResolutionChart struct {
+	pair   int
+	pic    *pixel.PictureData
+	picKey string
+}
+
+// resolutionWidths are the line widths of the bursts; a line pair is twice as wide.
+var resolutionWidths = []int{1, 2, 3, 4}
+
+var resolutionOrientations = []string{"horizontal", "vertical", "diagonal"}
+
+// resolutionPairs are the two line colors. The colored pairs keep luma
+// close and differ mostly in chroma, which subsampling blurs first.
+var resolutionPairs = []struct {
+	name string
+	a, b color.RGBA
+}{
+	{"white/black", color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 255}},
+	{"red/blue", color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}},
+	{"magenta/green", color.RGBA{255, 0, 255, 255}, color.RGBA{0, 160, 0, 255}},
+}
+
+const (
+	resolutionLabelH = 20
+	wedgeLines       = 60 // line pairs in each wedge
+	wedgeSpread      = 0.5
+)
+
+func (t *ResolutionChart) Name() string { return "Resolution Chart" }
+func (t *ResolutionChart) Description() string {
+	return "Line-pair bursts of 1 to 4 px in three orientations and converging wedges to check native resolution and chroma detail (C: line colors)"
+}
+func (t *ResolutionChart) Order() int { return 43 }
+
+func (t *ResolutionChart) Options() core.TestOptions {
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra: map[string]interface{}{
+			"colors": resolutionPairs[t.pair].name,
+		},
+	}
+}
+
+func (t *ResolutionChart) HandleKeys(ctx *core.WindowContext) {
+	core.AdjustBrightnessWithKeys(ctx, 0.1)
+	if ctx.Win.JustPressed(pixelgl.KeyC) {
+		t.pair = (t.pair + 1) % len(resolutionPairs)
+	}
+}
+
+func (t *ResolutionChart) SetOption(key, value string) error {
+	if key != "colors" {
+		return core.ErrUnknownOption
+	}
+	for i, p := range resolutionPairs {
+		if p.name == value {
+			t.pair = i
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not one of white/black, red/blue, magenta/green", value)
+}
+
+func (t *ResolutionChart) Settings() map[string]string {
+	return map[string]string{"colors": resolutionPairs[t.pair].name}
+}
+
+func (t *ResolutionChart) Run(ctx *core.WindowContext) {
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+
+	bounds := ctx.Win.Bounds()
+	width := int(bounds.W())
+	height := int(bounds.H())
+
+	key := fmt.Sprintf("%d %dx%d %.2f", t.pair, width, height, ctx.Brightness)
+	if t.pic == nil || t.picKey != key {
+		t.picKey = key
+		t.pic = pixel.MakePictureData(bounds)
+		pair := resolutionPairs[t.pair]
+		a := core.AdjustBrightness(pair.a, ctx.Brightness)
+		b := core.AdjustBrightness(pair.b, ctx.Brightness)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				c := colornames.Black
+				if lit, ok := t.patternAt(x, y, width, height); ok {
+					c = b
+					if lit {
+						c = a
+					}
+				}
+				t.pic.Pix[y*width+x] = c
+			}
+		}
+	}
+
+	sprite := pixel.NewSprite(t.pic, bounds)
+	sprite.Draw(ctx.Win, pixel.IM.Moved(bounds.Center()))
+
+	t.drawLabels(ctx, width, height)
+}
+
+// patternAt reports whether pixel (x, y) shows the first line color; ok is
+// false for the label strips and gaps between patches.
+func (t *ResolutionChart) patternAt(x, y, width, height int) (lit, ok bool) {
+	half := height / 2
+
+	if y >= half {
+		// Upper half: one row per orientation, one column per line width.
+		rowH := (height - half) / len(resolutionOrientations)
+		row := (height - 1 - y) / rowH
+		colW := width / len(resolutionWidths)
+		col := x / colW
+		if row >= len(resolutionOrientations) || col >= len(resolutionWidths) {
+			return false, false
+		}
+		ly := (height - 1 - y) % rowH
+		lx := x % colW
+		if ly < resolutionLabelH || ly >= rowH-4 || lx < 4 || lx >= colW-4 {
+			return false, false
+		}
+
+		w := resolutionWidths[col]
+		switch resolutionOrientations[row] {
+		case "horizontal":
+			return (y/w)%2 == 0, true
+		case "vertical":
+			return (x/w)%2 == 0, true
+		default:
+			return ((x+y)/w)%2 == 0, true
+		}
+	}
+
+	// Lower half: the left wedge has horizontal lines converging to the
+	// right, the right wedge vertical lines converging downwards.
+	if y >= half-resolutionLabelH {
+		return false, false
+	}
+	if x < width/2 {
+		apex := pixel.V(float64(width/2-8), float64(half/2))
+		return wedgeAt(float64(x)-apex.X, float64(y)-apex.Y, false)
+	}
+	apex := pixel.V(float64(width*3/4), 8)
+	return wedgeAt(float64(x)-apex.X, float64(y)-apex.Y, true)
+}
+
+// wedgeAt classifies a point relative to the wedge apex. Lines fan out over
+// wedgeSpread radians, so the line-pair width grows linearly with distance.
+func wedgeAt(dx, dy float64, vertical bool) (lit, ok bool) {
+	along, across := -dx, dy
+	if vertical {
+		along, across = dy, dx
+	}
+	if along <= 0 {
+		return false, false
+	}
+	angle := math.Atan2(across, along)
+	if math.Abs(angle) > wedgeSpread/2 {
+		return false, false
+	}
+	n := int(math.Floor((angle + wedgeSpread/2) / wedgeSpread * 2 * wedgeLines))
+	return n%2 == 0, true
+}
+
+// wedgeDistance is the distance from the apex where a wedge line pair is pairPx wide.
+func wedgeDistance(pairPx float64) float64 {
+	return pairPx * wedgeLines / wedgeSpread
+}
+
+func (t *ResolutionChart) drawLabels(ctx *core.WindowContext, width, height int) {
+	txt := text.New(pixel.ZV, ui.Atlas)
+	txt.Color = colornames.White
+
+	half := height / 2
+	rowH := (height - half) / len(resolutionOrientations)
+	colW := width / len(resolutionWidths)
+	for row, o := range resolutionOrientations {
+		for col, w := range resolutionWidths {
+			txt.Dot = pixel.V(float64(col*colW+6), float64(height-row*rowH-14))
+			fmt.Fprintf(txt, "%s %d px lines (%d px/pair)", o, w, w*2)
+		}
+	}
+
+	// Wedge marks where the line pairs are 2, 4, 6 and 8 pixels wide.
+	for _, w := range resolutionWidths {
+		d := wedgeDistance(float64(w * 2))
+		x := float64(width/2-8) - d
+		if x > 0 {
+			txt.Dot = pixel.V(x, float64(half-resolutionLabelH+4))
+			fmt.Fprintf(txt, "%dpx", w)
+		}
+		y := 8 + d
+		if y < float64(half-resolutionLabelH) {
+			txt.Dot = pixel.V(float64(width*3/4)+float64(width/8), y)
+			fmt.Fprintf(txt, "%dpx", w)
+		}
+	}
+
+	txt.Draw(ctx.Win, pixel.IM)
+}
+
+func init() {
+	core.RegisterTest(&ResolutionChart{})
+}
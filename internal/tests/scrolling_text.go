@@ -0,0 +1,290 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/ui"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+)
+
+type ScrollingText struct {
+	state       *scrollState
+	defaultStep int
+	minStep     int
+	maxStep     int
+}
+
+type scrollState struct {
+	mode     string // "marquee" or "page"
+	step     int    // pixels per frame
+	scale    int
+	inverted bool
+	offset   int
+	hz       float64 // frame rate the px/s figure is based on
+	nominal  float64 // display refresh rate px/s settings are rounded at
+	marquee  *text.Text
+	page     *text.Text
+	pageCols int
+}
+
+var scrollParagraphs = []string{
+	"The quick brown fox jumps over the lazy dog. Pack my box with five dozen liquor jugs. Sphinx of black quartz, judge my vow.",
+	"Motion blur on sample-and-hold displays comes from the eye tracking a moving object while every frame stays lit for the whole refresh period. The faster the text moves, the wider the smear becomes, and thin strokes of small letters are the first to disappear.",
+	"Overdrive that is too weak leaves a trail behind each glyph, while overdrive that is too strong draws a bright or dark halo on the opposite side. Both are easy to see on black text moving over a white page.",
+	"Office users rarely watch bouncing balls. They scroll spreadsheets, chat windows and long documents all day, and that is where ghosting and smearing are noticed first.",
+	"0123456789 ABCDEFGHIJKLMNOPQRSTUVWXYZ abcdefghijklmnopqrstuvwxyz !?.,;:'\"()[]{}<>+-*/=_#%&@",
+}
+
+func (t *ScrollingText) Name() string { return "Scrolling Text" }
+func (t *ScrollingText) Description() string {
+	return "Text marquee and scrolling page for motion readability (Shift+Up/Down: px per frame, also settable in px/s; M: marquee/page, F: font scale, I: invert)"
+}
+func (t *ScrollingText) Order() int { return 52 }
+
+func (t *ScrollingText) Options() core.TestOptions {
+	t.ensureState()
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra: map[string]interface{}{
+			"mode":     t.state.mode,
+			"step":     fmt.Sprintf("%d px/frame", t.state.step),
+			"velocity": fmt.Sprintf("%.0f px/s at %.1f Hz", float64(t.state.step)*t.state.hz, t.state.hz),
+			"scale":    t.state.scale,
+			"inverted": t.state.inverted,
+		},
+	}
+}
+
+func (t *ScrollingText) HandleKeys(ctx *core.WindowContext) {
+	win := ctx.Win
+
+	if win.Pressed(pixelgl.KeyLeftShift) || win.Pressed(pixelgl.KeyRightShift) {
+		if win.JustPressed(pixelgl.KeyUp) {
+			t.setStep(t.state.step + 1)
+		}
+		if win.JustPressed(pixelgl.KeyDown) {
+			t.setStep(t.state.step - 1)
+		}
+	} else {
+		core.AdjustBrightnessWithKeys(ctx, 0.1)
+	}
+
+	if win.JustPressed(pixelgl.KeyM) {
+		if t.state.mode == "marquee" {
+			t.state.mode = "page"
+		} else {
+			t.state.mode = "marquee"
+		}
+		t.state.offset = 0
+	}
+	if win.JustPressed(pixelgl.KeyF) {
+		t.state.scale = t.state.scale%3 + 1
+		t.state.page = nil
+	}
+	if win.JustPressed(pixelgl.KeyI) {
+		t.state.inverted = !t.state.inverted
+	}
+}
+
+func (t *ScrollingText) SetOption(key, value string) error {
+	t.ensureState()
+	switch key {
+	case "mode":
+		i, err := core.Choose(value, []string{"marquee", "page"})
+		if err != nil {
+			return err
+		}
+		t.state.mode = []string{"marquee", "page"}[i]
+		t.state.offset = 0
+	case "step":
+		step, err := t.parseStep(value)
+		if err != nil {
+			return err
+		}
+		t.setStep(step)
+	case "scale":
+		scale, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		t.state.scale = clampInt(scale, 1, 3)
+		t.state.page = nil
+	case "inverted":
+		on, err := core.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		t.state.inverted = on
+	default:
+		return core.ErrUnknownOption
+	}
+	return nil
+}
+
+func (t *ScrollingText) Settings() map[string]string {
+	t.ensureState()
+	return map[string]string{
+		"mode":     t.state.mode,
+		"step":     strconv.Itoa(t.state.step),
+		"scale":    strconv.Itoa(t.state.scale),
+		"inverted": strconv.FormatBool(t.state.inverted),
+	}
+}
+
+func (t *ScrollingText) Run(ctx *core.WindowContext) {
+	t.ensureState()
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+	t.state.hz = frameRate(ctx)
+
+	bounds := ctx.Win.Bounds()
+	paper := core.AdjustBrightness(color.RGBA{255, 255, 255, 255}, ctx.Brightness)
+	ink := color.RGBA{0, 0, 0, 255}
+	if t.state.inverted {
+		paper, ink = ink, paper
+	}
+
+	ctx.Win.Clear(paper)
+
+	// Movement is a whole number of pixels per frame, so every frame shifts
+	// the text by exactly the same distance regardless of frame time jitter.
+	t.state.offset += t.state.step
+	scale := float64(t.state.scale)
+
+	if t.state.mode == "marquee" {
+		txtW := t.state.marquee.Bounds().W() * scale
+		span := int(bounds.W() + txtW)
+		t.state.offset %= span
+		x := bounds.W() - float64(t.state.offset)
+		y := float64(int(bounds.H() / 2))
+
+		t.drawText(ctx, t.state.marquee, x, y, ink)
+		return
+	}
+
+	cols := int(bounds.W()/(7*scale)) - 8
+	if t.state.page == nil || t.state.pageCols != cols {
+		t.state.page = buildPage(cols)
+		t.state.pageCols = cols
+	}
+
+	pageH := t.state.page.Bounds().H() * scale
+	span := int(bounds.H() + pageH)
+	t.state.offset %= span
+	margin := float64(int(4 * 7 * scale))
+	t.drawText(ctx, t.state.page, margin, float64(t.state.offset), ink)
+}
+
+// drawText draws txt with its top-left corner at (x, y) in window coordinates.
+func (t *ScrollingText) drawText(ctx *core.WindowContext, txt *text.Text, x, y float64, ink color.RGBA) {
+	scale := float64(t.state.scale)
+	top := txt.Bounds().Max.Y * scale
+	ctx.Win.SetColorMask(ink)
+	txt.Draw(ctx.Win, pixel.IM.Scaled(pixel.ZV, scale).Moved(pixel.V(x, y-top)))
+	ctx.Win.SetColorMask(pixel.Alpha(1))
+}
+
+func buildPage(cols int) *text.Text {
+	if cols < 20 {
+		cols = 20
+	}
+	txt := text.New(pixel.ZV, ui.Atlas)
+	txt.LineHeight = ui.Atlas.LineHeight() * 1.5
+	for i := 0; i < 6; i++ {
+		for _, p := range scrollParagraphs {
+			fmt.Fprintln(txt, strings.Join(core.WrapText(p, cols), "\n"))
+			fmt.Fprintln(txt)
+		}
+	}
+	return txt
+}
+
+func (t *ScrollingText) ensureState() {
+	if t.state != nil {
+		return
+	}
+	marquee := text.New(pixel.ZV, ui.Atlas)
+	fmt.Fprint(marquee, strings.Join(scrollParagraphs, "   "))
+
+	t.state = &scrollState{
+		mode:    "marquee",
+		step:    t.defaultStep,
+		scale:   2,
+		marquee: marquee,
+		hz:      refreshRate(),
+	}
+	t.state.nominal = t.state.hz
+}
+
+// parseStep reads a step in px/frame, also as the "N px/frame" it reports,
+// or a speed in px/s, which is rounded to whole pixels per frame at the
+// display's refresh rate.
+func (t *ScrollingText) parseStep(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	perSecond := false
+	if v, ok := strings.CutSuffix(value, "px/s"); ok {
+		value, perSecond = v, true
+	} else {
+		value = strings.TrimSuffix(value, "px/frame")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	if perSecond {
+		n = int(math.Round(float64(n) / t.state.nominal))
+	}
+	return n, nil
+}
+
+func (t *ScrollingText) setStep(step int) {
+	if step < t.minStep {
+		step = t.minStep
+	}
+	if step > t.maxStep {
+		step = t.maxStep
+	}
+	t.state.step = step
+}
+
+// rateSamples is how many frame intervals are timed before the measured
+// rate is trusted over the monitor's nominal one.
+const rateSamples = 60
+
+// frameRate returns the measured frame rate once enough frames have been
+// timed, and the monitor's refresh rate before that.
+func frameRate(ctx *core.WindowContext) float64 {
+	if ctx.Frames != nil {
+		if stats := ctx.Frames.Stats(); stats.Samples >= rateSamples && stats.MeasuredHz > 0 {
+			return stats.MeasuredHz
+		}
+	}
+	return refreshRate()
+}
+
+// refreshRate reports the primary monitor refresh rate, falling back to 60 Hz.
+func refreshRate() float64 {
+	if m := pixelgl.PrimaryMonitor(); m != nil {
+		if rate := m.RefreshRate(); rate > 0 {
+			return rate
+		}
+	}
+	return 60
+}
+
+func init() {
+	core.RegisterTest(&ScrollingText{
+		defaultStep: 4,
+		minStep:     1,
+		maxStep:     32,
+	})
+}
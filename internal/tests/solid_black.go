@@ -24,7 +24,9 @@ func (t *SolidBlack) HandleKeys(ctx *core.WindowContext) {
 }
 
 func (t *SolidBlack) Run(ctx *core.WindowContext) {
-	t.HandleKeys(ctx)
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
 	ctx.Win.Clear(core.AdjustBrightness(color.RGBA{0, 0, 0, 255}, ctx.Brightness))
 }
 
@@ -26,7 +26,9 @@ func (t *SolidBlue) HandleKeys(ctx *core.WindowContext) {
 }
 
 func (t *SolidBlue) Run(ctx *core.WindowContext) {
-	t.HandleKeys(ctx)
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
 	ctx.Win.Clear(core.AdjustBrightness(color.RGBA{0, 0, 255, 255}, ctx.Brightness))
 }
 
@@ -26,7 +26,9 @@ func (t *SolidGreen) HandleKeys(ctx *core.WindowContext) {
 }
 
 func (t *SolidGreen) Run(ctx *core.WindowContext) {
-	t.HandleKeys(ctx)
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
 	ctx.Win.Clear(core.AdjustBrightness(color.RGBA{0, 255, 0, 255}, ctx.Brightness))
 }
 
@@ -26,7 +26,9 @@ func (t *SolidRed) HandleKeys(ctx *core.WindowContext) {
 }
 
 func (t *SolidRed) Run(ctx *core.WindowContext) {
-	t.HandleKeys(ctx)
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
 	ctx.Win.Clear(core.AdjustBrightness(color.RGBA{255, 0, 0, 255}, ctx.Brightness))
 }
 
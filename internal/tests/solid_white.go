@@ -26,7 +26,9 @@ func (t *SolidWhite) HandleKeys(ctx *core.WindowContext) {
 }
 
 func (t *SolidWhite) Run(ctx *core.WindowContext) {
-	t.HandleKeys(ctx)
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
 	ctx.Win.Clear(core.AdjustBrightness(color.RGBA{255, 255, 255, 255}, ctx.Brightness))
 }
 
@@ -0,0 +1,228 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/ui"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+type TemporalDither struct {
+	base     int
+	minBase  int
+	maxBase  int
+	step     int
+	dither   bool
+	channel  int
+	frame    int
+	phases   []*pixel.PictureData // one picture per dither phase
+	phaseKey string
+}
+
+const (
+	ditherPatches = 16
+	ditherPhases  = 4 // quarter-LSB resolution
+)
+
+var ditherChannels = []struct {
+	name string
+	mask color.RGBA
+}{
+	{"gray", color.RGBA{1, 1, 1, 1}},
+	{"red", color.RGBA{1, 0, 0, 1}},
+	{"green", color.RGBA{0, 1, 0, 1}},
+	{"blue", color.RGBA{0, 0, 1, 1}},
+}
+
+func (t *TemporalDither) Name() string { return "Temporal Dithering" }
+func (t *TemporalDither) Description() string {
+	return "Patches one code value apart and a static vs dithered field to spot 6-bit+FRC panels and pipeline dithering (Shift+Up/Down: base level, D: quarter-step dithered patches, C: channel)"
+}
+func (t *TemporalDither) Order() int { return 22 }
+
+func (t *TemporalDither) Options() core.TestOptions {
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra: map[string]interface{}{
+			"base":    t.base,
+			"dither":  t.dither,
+			"channel": ditherChannels[t.channel].name,
+		},
+	}
+}
+
+func (t *TemporalDither) HandleKeys(ctx *core.WindowContext) {
+	win := ctx.Win
+
+	if win.Pressed(pixelgl.KeyLeftShift) || win.Pressed(pixelgl.KeyRightShift) {
+		if win.JustPressed(pixelgl.KeyUp) {
+			t.base = clampInt(t.base+t.step, t.minBase, t.maxBase)
+		}
+		if win.JustPressed(pixelgl.KeyDown) {
+			t.base = clampInt(t.base-t.step, t.minBase, t.maxBase)
+		}
+	} else {
+		core.AdjustBrightnessWithKeys(ctx, 0.1)
+	}
+
+	if win.JustPressed(pixelgl.KeyD) {
+		t.dither = !t.dither
+	}
+	if win.JustPressed(pixelgl.KeyC) {
+		t.channel = (t.channel + 1) % len(ditherChannels)
+	}
+}
+
+func (t *TemporalDither) SetOption(key, value string) error {
+	switch key {
+	case "base":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		t.base = clampInt(n, t.minBase, t.maxBase)
+	case "dither":
+		on, err := core.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		t.dither = on
+	case "channel":
+		for i, c := range ditherChannels {
+			if c.name == value {
+				t.channel = i
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of gray, red, green, blue", value)
+	default:
+		return core.ErrUnknownOption
+	}
+	return nil
+}
+
+func (t *TemporalDither) Settings() map[string]string {
+	return map[string]string{
+		"base":    strconv.Itoa(t.base),
+		"dither":  strconv.FormatBool(t.dither),
+		"channel": ditherChannels[t.channel].name,
+	}
+}
+
+func (t *TemporalDither) Run(ctx *core.WindowContext) {
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+	t.frame++
+
+	bounds := ctx.Win.Bounds()
+	width := int(bounds.W())
+	height := int(bounds.H())
+
+	key := fmt.Sprintf("%d %v %d %dx%d %.2f", t.base, t.dither, t.channel, width, height, ctx.Brightness)
+	if t.phaseKey != key {
+		t.phaseKey = key
+		t.phases = make([]*pixel.PictureData, ditherPhases)
+		for p := range t.phases {
+			t.phases[p] = t.render(bounds, p, ctx.Brightness)
+		}
+	}
+
+	// Cycling the precomputed phases animates the dither without refilling
+	// the whole picture every frame.
+	pic := t.phases[t.frame%ditherPhases]
+	sprite := pixel.NewSprite(pic, bounds)
+	sprite.Draw(ctx.Win, pixel.IM.Moved(bounds.Center()))
+
+	t.drawLabels(ctx, width, height)
+}
+
+// render fills one dither phase. The upper half holds the patches, the
+// lower half the static (left) and dithered (right) comparison field.
+func (t *TemporalDither) render(bounds pixel.Rect, phase int, brightness float64) *pixel.PictureData {
+	width := int(bounds.W())
+	height := int(bounds.H())
+	pic := pixel.MakePictureData(bounds)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			// Levels are in quarter code values.
+			var level int
+			if y >= height/2 {
+				patch := x * ditherPatches / width
+				if t.dither {
+					level = t.base*ditherPhases + patch
+				} else {
+					level = (t.base + patch) * ditherPhases
+				}
+			} else {
+				level = t.base * ditherPhases
+				if x >= width/2 {
+					level += ditherPhases / 2
+				}
+			}
+			pic.Pix[y*width+x] = t.ditheredColor(level, x, y, phase, brightness)
+		}
+	}
+	return pic
+}
+
+// ditheredColor turns a level in quarter code values into a code value by
+// lighting the next code on a fraction of pixels. The threshold rotates with
+// the phase, so every pixel averages to the requested level over time.
+// Brightness scales the level before dithering; scaling the dithered codes
+// would merge neighbouring ones.
+func (t *TemporalDither) ditheredColor(level, x, y, phase int, brightness float64) color.RGBA {
+	level = int(math.Round(float64(level) * brightness))
+	v := level / ditherPhases
+	frac := level % ditherPhases
+	threshold := (x%2*2 + y%2 + phase) % ditherPhases
+	if threshold < frac {
+		v++
+	}
+	if v > 255 {
+		v = 255
+	}
+
+	m := ditherChannels[t.channel].mask
+	return color.RGBA{uint8(v) * m.R, uint8(v) * m.G, uint8(v) * m.B, 255}
+}
+
+func (t *TemporalDither) drawLabels(ctx *core.WindowContext, width, height int) {
+	txt := text.New(pixel.ZV, ui.Atlas)
+	txt.Color = colornames.Magenta
+
+	patchW := float64(width) / ditherPatches
+	for i := 0; i < ditherPatches; i++ {
+		txt.Dot = pixel.V(float64(i)*patchW+6, float64(height)-20)
+		if t.dither {
+			fmt.Fprintf(txt, "%.2f", float64(t.base)+float64(i)/ditherPhases)
+		} else {
+			fmt.Fprintf(txt, "%d", t.base+i)
+		}
+	}
+
+	txt.Dot = pixel.V(10, float64(height/2)-20)
+	fmt.Fprintf(txt, "STATIC %d", t.base)
+	txt.Dot = pixel.V(float64(width/2)+10, float64(height/2)-20)
+	fmt.Fprintf(txt, "DITHERED %.1f", float64(t.base)+0.5)
+
+	txt.Draw(ctx.Win, pixel.IM)
+}
+
+func init() {
+	core.RegisterTest(&TemporalDither{
+		base:    120,
+		minBase: 0,
+		maxBase: 255 - ditherPatches,
+		step:    4,
+	})
+}
@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"unicode/utf8"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/ui"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+type TestCard struct {
+	label   string
+	editing bool
+	pic     *pixel.PictureData
+	picKey  string
+}
+
+const testCardRows = 14 // grid cells from top to bottom; columns follow the aspect ratio
+
+var testCardBars = []color.RGBA{
+	{255, 255, 0, 255}, {0, 255, 255, 255}, {0, 255, 0, 255},
+	{255, 0, 255, 255}, {255, 0, 0, 255}, {0, 0, 255, 255},
+}
+
+// testCardBursts are the line widths of the resolution bursts, in pixels.
+var testCardBursts = []int{1, 2, 3, 4}
+
+func (t *TestCard) Name() string { return "Test Card" }
+func (t *TestCard) Description() string {
+	return "PM5544-style composite card with circle, grid, color bars, gray steps, resolution bursts and an editable label (T: edit label, Enter: done)"
+}
+func (t *TestCard) Order() int { return 7 }
+
+func (t *TestCard) Options() core.TestOptions {
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra: map[string]interface{}{
+			"label": t.label,
+		},
+	}
+}
+
+func (t *TestCard) HandleKeys(ctx *core.WindowContext) {
+	win := ctx.Win
+	defer func() { ctx.TextEntry = t.editing }()
+
+	if !t.editing {
+		core.AdjustBrightnessWithKeys(ctx, 0.1)
+		if win.JustPressed(pixelgl.KeyT) {
+			t.editing = true
+		}
+		return
+	}
+
+	t.label += win.Typed()
+	if (win.JustPressed(pixelgl.KeyBackspace) || win.Repeated(pixelgl.KeyBackspace)) && len(t.label) > 0 {
+		_, size := utf8.DecodeLastRuneInString(t.label)
+		t.label = t.label[:len(t.label)-size]
+	}
+	if win.JustPressed(pixelgl.KeyEnter) || win.JustPressed(pixelgl.KeyKPEnter) {
+		t.editing = false
+	}
+}
+
+func (t *TestCard) SetOption(key, value string) error {
+	if key != "label" {
+		return core.ErrUnknownOption
+	}
+	t.label = value
+	return nil
+}
+
+func (t *TestCard) Settings() map[string]string {
+	return map[string]string{"label": t.label}
+}
+
+func (t *TestCard) Run(ctx *core.WindowContext) {
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+
+	bounds := ctx.Win.Bounds()
+	width := int(bounds.W())
+	height := int(bounds.H())
+
+	key := fmt.Sprintf("%dx%d %.2f", width, height, ctx.Brightness)
+	if t.pic == nil || t.picKey != key {
+		t.picKey = key
+		t.pic = pixel.MakePictureData(bounds)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				t.pic.Pix[y*width+x] = core.AdjustBrightness(testCardColorAt(x, y, width, height), ctx.Brightness)
+			}
+		}
+	}
+
+	sprite := pixel.NewSprite(t.pic, bounds)
+	sprite.Draw(ctx.Win, pixel.IM.Moved(bounds.Center()))
+
+	t.drawLabel(ctx, width, height)
+}
+
+// testCardColorAt returns the card color at pixel (x, y), y growing upwards.
+// Everything is derived from the screen height so the card keeps its shape
+// on any resolution and aspect ratio.
+func testCardColorAt(x, y, width, height int) color.RGBA {
+	cell := float64(height) / testCardRows
+	cx := float64(width) / 2
+	cy := float64(height) / 2
+	r := cell * 6
+	fx := float64(x) + 0.5
+	fy := float64(y) + 0.5
+
+	white := color.RGBA{255, 255, 255, 255}
+	black := color.RGBA{0, 0, 0, 255}
+
+	// Outermost pixel frame reveals overscan.
+	if x == 0 || y == 0 || x == width-1 || y == height-1 {
+		return white
+	}
+
+	// Center cross, one pixel wide, across the whole circle.
+	ix, iy := int(cx), int(cy)
+	if math.Hypot(fx-cx, fy-cy) <= r && (x == ix || y == iy) {
+		return white
+	}
+
+	dx := fx - cx
+	dy := fy - cy
+	if math.Hypot(dx, dy) > r {
+		// Background grid, lines centred on the cell boundaries from the middle out.
+		gx := math.Mod(math.Abs(dx), cell)
+		gy := math.Mod(math.Abs(dy), cell)
+		if gx < 1 || gy < 1 {
+			return white
+		}
+		return color.RGBA{64, 64, 64, 255}
+	}
+
+	// Inside the circle: horizontal bands from top to bottom.
+	band := (r - dy) / (2 * r) // 0 at the top of the circle, 1 at the bottom
+	across := (dx + r) / (2 * r)
+	switch {
+	case band < 0.2:
+		return testCardBars[int(across*float64(len(testCardBars)))%len(testCardBars)]
+
+	case band < 0.35:
+		steps := 6
+		v := uint8(255 * (int(across*float64(steps)) % steps) / (steps - 1))
+		return color.RGBA{v, v, v, 255}
+
+	case band < 0.55:
+		// Resolution bursts: alternating lines of 1, 2, 3 and 4 pixels.
+		section := int(across * float64(len(testCardBursts)))
+		if section >= len(testCardBursts) {
+			section = len(testCardBursts) - 1
+		}
+		w := testCardBursts[section]
+		if (x/w)%2 == 0 {
+			return white
+		}
+		return black
+
+	case band < 0.65:
+		if across < 0.5 {
+			return white
+		}
+		return black
+
+	case band < 0.8:
+		return black // label box, text is drawn on top
+
+	default:
+		v := uint8(255 * across)
+		return color.RGBA{v, 0, 255 - v, 255}
+	}
+}
+
+func (t *TestCard) drawLabel(ctx *core.WindowContext, width, height int) {
+	cell := float64(height) / testCardRows
+	r := cell * 6
+	// Middle of the label band (0.65..0.8 of the circle from the top).
+	centerY := float64(height)/2 + r - 2*r*0.725
+
+	label := t.label
+	if t.editing {
+		label += "_"
+	}
+
+	txt := text.New(pixel.ZV, ui.Atlas)
+	txt.Color = colornames.White
+	fmt.Fprint(txt, label)
+	scale := math.Max(1, math.Floor(cell*0.8/ui.Atlas.LineHeight()))
+	for scale > 1 && txt.Bounds().W()*scale > r*1.6 {
+		scale--
+	}
+	pos := pixel.V(
+		math.Floor(float64(width)/2-txt.Bounds().W()*scale/2),
+		math.Floor(centerY-txt.Bounds().H()*scale/2+ui.Atlas.Descent()*scale),
+	)
+	txt.Draw(ctx.Win, pixel.IM.Scaled(pixel.ZV, scale).Moved(pos))
+}
+
+func init() {
+	core.RegisterTest(&TestCard{
+		label: "SCREEN TESTER",
+	})
+}
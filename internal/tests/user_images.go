@@ -0,0 +1,267 @@
+package tests
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/ui"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+type UserImages struct {
+	state *imagesState
+	modes []string
+	mode  int
+	file  string // file option set before the images were scanned
+}
+
+type imagesState struct {
+	source  string
+	files   []string
+	index   int
+	loaded  int // index of the decoded picture, -1 when nothing is loaded
+	pic     *pixel.PictureData
+	sprite  *pixel.Sprite
+	loadErr error
+}
+
+func (t *UserImages) Name() string { return "User Images" }
+func (t *UserImages) Description() string {
+	return "PNG/JPEG files from the -images directory or file (Shift+Up/Down: image, M: scaling mode, R: reload)"
+}
+func (t *UserImages) Order() int { return 70 }
+
+func (t *UserImages) Options() core.TestOptions {
+	extra := map[string]interface{}{
+		"mode": t.modes[t.mode],
+	}
+	if t.state != nil {
+		if len(t.state.files) > 0 {
+			extra["file"] = fmt.Sprintf("%s (%d/%d)", filepath.Base(t.state.files[t.state.index]), t.state.index+1, len(t.state.files))
+		}
+		if t.state.pic != nil {
+			extra["image"] = fmt.Sprintf("%.0fx%.0f", t.state.pic.Bounds().W(), t.state.pic.Bounds().H())
+		}
+	}
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra:      extra,
+	}
+}
+
+func (t *UserImages) HandleKeys(ctx *core.WindowContext) {
+	win := ctx.Win
+
+	if win.Pressed(pixelgl.KeyLeftShift) || win.Pressed(pixelgl.KeyRightShift) {
+		if n := len(t.state.files); n > 0 {
+			if win.JustPressed(pixelgl.KeyUp) {
+				t.state.index = (t.state.index + 1) % n
+			}
+			if win.JustPressed(pixelgl.KeyDown) {
+				t.state.index = (t.state.index - 1 + n) % n
+			}
+		}
+	} else {
+		core.AdjustBrightnessWithKeys(ctx, 0.1)
+	}
+
+	if win.JustPressed(pixelgl.KeyM) {
+		t.mode = (t.mode + 1) % len(t.modes)
+	}
+	if win.JustPressed(pixelgl.KeyR) {
+		t.scan(t.state.source)
+	}
+}
+
+func (t *UserImages) Run(ctx *core.WindowContext) {
+	if t.state == nil || t.state.source != ctx.ImagePath {
+		t.state = &imagesState{}
+		t.scan(ctx.ImagePath)
+		if t.file != "" {
+			if err := t.selectFile(t.file); err != nil {
+				t.state.loaded, t.state.loadErr = t.state.index, err
+			}
+			t.file = ""
+		}
+	}
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+
+	ctx.Win.Clear(colornames.Black)
+
+	if len(t.state.files) == 0 {
+		t.drawMessage(ctx, fmt.Sprintf("No PNG or JPEG images found in %q", t.state.source))
+		return
+	}
+	if t.state.loaded != t.state.index {
+		t.load(t.state.index)
+	}
+	if t.state.loadErr != nil {
+		t.drawMessage(ctx, t.state.loadErr.Error())
+		return
+	}
+
+	bounds := ctx.Win.Bounds()
+	imgW := t.state.pic.Bounds().W()
+	imgH := t.state.pic.Bounds().H()
+
+	var mat pixel.Matrix
+	switch t.modes[t.mode] {
+	case "fit":
+		s := math.Min(bounds.W()/imgW, bounds.H()/imgH)
+		mat = pixel.IM.Scaled(pixel.ZV, s).Moved(bounds.Center())
+	case "fill":
+		s := math.Max(bounds.W()/imgW, bounds.H()/imgH)
+		mat = pixel.IM.Scaled(pixel.ZV, s).Moved(bounds.Center())
+	case "stretch":
+		mat = pixel.IM.ScaledXY(pixel.ZV, pixel.V(bounds.W()/imgW, bounds.H()/imgH)).Moved(bounds.Center())
+	default:
+		// Snap the image origin to a whole pixel so 1:1 stays pixel-exact
+		// when the image and screen sizes differ in parity.
+		x := math.Floor((bounds.W() - imgW) / 2)
+		y := math.Floor((bounds.H() - imgH) / 2)
+		mat = pixel.IM.Moved(pixel.V(x+imgW/2, y+imgH/2))
+	}
+
+	ctx.Win.SetColorMask(pixel.RGB(ctx.Brightness, ctx.Brightness, ctx.Brightness))
+	t.state.sprite.Draw(ctx.Win, mat)
+	ctx.Win.SetColorMask(pixel.Alpha(1))
+}
+
+func (t *UserImages) SetOption(key, value string) error {
+	switch key {
+	case "mode":
+		i, err := core.Choose(value, t.modes)
+		if err != nil {
+			return err
+		}
+		t.mode = i
+	case "file":
+		// Files are only known once the test has run with the image path,
+		// so until then the choice is kept for the first run.
+		if t.state == nil {
+			t.file = value
+			return nil
+		}
+		return t.selectFile(value)
+	default:
+		return core.ErrUnknownOption
+	}
+	return nil
+}
+
+// selectFile picks an image by its 1-based number or file name; an empty
+// value picks the first.
+func (t *UserImages) selectFile(value string) error {
+	if value == "" {
+		t.state.index = 0
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 1 && n <= len(t.state.files) {
+		t.state.index = n - 1
+		return nil
+	}
+	for i, f := range t.state.files {
+		if filepath.Base(f) == value {
+			t.state.index = i
+			return nil
+		}
+	}
+	return fmt.Errorf("no image %q in %q", value, t.state.source)
+}
+
+func (t *UserImages) Settings() map[string]string {
+	file := t.file
+	if t.state != nil && len(t.state.files) > 0 {
+		file = filepath.Base(t.state.files[t.state.index])
+	}
+	return map[string]string{
+		"mode": t.modes[t.mode],
+		"file": file,
+	}
+}
+
+// scan collects image files from source, which may be a single file or a directory.
+func (t *UserImages) scan(source string) {
+	t.state.source = source
+	t.state.files = nil
+	t.state.index = 0
+	t.state.loaded = -1
+	t.state.pic = nil
+	t.state.sprite = nil
+	t.state.loadErr = nil
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return
+	}
+	if !info.IsDir() {
+		t.state.files = []string{source}
+		return
+	}
+
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".png", ".jpg", ".jpeg":
+			t.state.files = append(t.state.files, filepath.Join(source, e.Name()))
+		}
+	}
+	sort.Strings(t.state.files)
+}
+
+func (t *UserImages) load(index int) {
+	t.state.loaded = index
+	t.state.pic = nil
+	t.state.sprite = nil
+	t.state.loadErr = nil
+
+	f, err := os.Open(t.state.files[index])
+	if err != nil {
+		t.state.loadErr = err
+		return
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		t.state.loadErr = fmt.Errorf("%s: %w", filepath.Base(t.state.files[index]), err)
+		return
+	}
+
+	t.state.pic = pixel.PictureDataFromImage(img)
+	t.state.sprite = pixel.NewSprite(t.state.pic, t.state.pic.Bounds())
+}
+
+func (t *UserImages) drawMessage(ctx *core.WindowContext, msg string) {
+	txt := text.New(pixel.ZV, ui.Atlas)
+	txt.Color = colornames.White
+	fmt.Fprint(txt, msg)
+	txt.Draw(ctx.Win, pixel.IM.Moved(ctx.Win.Bounds().Center().Sub(txt.Bounds().Center())))
+}
+
+func init() {
+	core.RegisterTest(&UserImages{
+		modes: []string{"1:1", "fit", "fill", "stretch"},
+	})
+}
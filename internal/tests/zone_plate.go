@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/keshon/screen-tester/internal/core"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+type ZonePlate struct {
+	modes      []string
+	mode       int
+	freq       float64 // maximum frequency in cycles per pixel, 0.5 is Nyquist
+	minFreq    float64
+	maxFreq    float64
+	freqStep   float64
+	contrast   int
+	angleIndex int
+	pic        *pixel.PictureData
+	picKey     string
+}
+
+var zoneContrasts = []int{100, 75, 50, 25, 10}
+
+// zoneAngles are moiré line angles in degrees; small fractional tilts beat
+// against the pixel grid and reveal resampling.
+var zoneAngles = []float64{0.5, 1, 2.5, 5, 14.5, 30, 44.5}
+
+func (t *ZonePlate) Name() string { return "Zone Plate" }
+func (t *ZonePlate) Description() string {
+	return "Circular and linear zone plates and tilted line patterns for scaling, aliasing and focus checks (M: pattern, Shift+Up/Down: max frequency, C: contrast, A: line angle)"
+}
+func (t *ZonePlate) Order() int { return 42 }
+
+func (t *ZonePlate) Options() core.TestOptions {
+	extra := map[string]interface{}{
+		"mode":      t.modes[t.mode],
+		"frequency": fmt.Sprintf("%.2f cycles/px", t.freq),
+		"contrast":  fmt.Sprintf("%d%%", zoneContrasts[t.contrast]),
+	}
+	if t.modes[t.mode] == "moire lines" {
+		extra["angle"] = fmt.Sprintf("%.1f deg", zoneAngles[t.angleIndex])
+	}
+	return core.TestOptions{
+		Brightness: 1.0,
+		Extra:      extra,
+	}
+}
+
+func (t *ZonePlate) HandleKeys(ctx *core.WindowContext) {
+	win := ctx.Win
+
+	if win.Pressed(pixelgl.KeyLeftShift) || win.Pressed(pixelgl.KeyRightShift) {
+		if win.JustPressed(pixelgl.KeyUp) {
+			t.freq = core.Clamp(t.freq+t.freqStep, t.minFreq, t.maxFreq)
+		}
+		if win.JustPressed(pixelgl.KeyDown) {
+			t.freq = core.Clamp(t.freq-t.freqStep, t.minFreq, t.maxFreq)
+		}
+	} else {
+		core.AdjustBrightnessWithKeys(ctx, 0.1)
+	}
+
+	if win.JustPressed(pixelgl.KeyM) {
+		t.mode = (t.mode + 1) % len(t.modes)
+	}
+	if win.JustPressed(pixelgl.KeyC) {
+		t.contrast = (t.contrast + 1) % len(zoneContrasts)
+	}
+	if win.JustPressed(pixelgl.KeyA) {
+		t.angleIndex = (t.angleIndex + 1) % len(zoneAngles)
+	}
+}
+
+func (t *ZonePlate) SetOption(key, value string) error {
+	switch key {
+	case "mode":
+		i, err := core.Choose(value, t.modes)
+		if err != nil {
+			return err
+		}
+		t.mode = i
+	case "frequency":
+		f, err := strconv.ParseFloat(strings.TrimSuffix(value, " cycles/px"), 64)
+		if err != nil {
+			return err
+		}
+		t.freq = core.Clamp(f, t.minFreq, t.maxFreq)
+	case "contrast":
+		p, err := core.ParsePercent(value)
+		if err != nil {
+			return err
+		}
+		for i, c := range zoneContrasts {
+			if c == p {
+				t.contrast = i
+				return nil
+			}
+		}
+		return fmt.Errorf("%d%% is not one of %v", p, zoneContrasts)
+	case "angle":
+		a, err := strconv.ParseFloat(strings.TrimSuffix(value, " deg"), 64)
+		if err != nil {
+			return err
+		}
+		for i, z := range zoneAngles {
+			if z == a {
+				t.angleIndex = i
+				return nil
+			}
+		}
+		return fmt.Errorf("%g is not one of %v", a, zoneAngles)
+	default:
+		return core.ErrUnknownOption
+	}
+	return nil
+}
+
+func (t *ZonePlate) Settings() map[string]string {
+	return map[string]string{
+		"mode":      t.modes[t.mode],
+		"frequency": fmt.Sprintf("%.2f cycles/px", t.freq),
+		"contrast":  fmt.Sprintf("%d%%", zoneContrasts[t.contrast]),
+		"angle":     fmt.Sprintf("%.1f deg", zoneAngles[t.angleIndex]),
+	}
+}
+
+func (t *ZonePlate) Run(ctx *core.WindowContext) {
+	if !ctx.InputBlocked {
+		t.HandleKeys(ctx)
+	}
+
+	bounds := ctx.Win.Bounds()
+	width := int(bounds.W())
+	height := int(bounds.H())
+
+	key := fmt.Sprintf("%d %.2f %d %d %dx%d %.2f", t.mode, t.freq, t.contrast, t.angleIndex, width, height, ctx.Brightness)
+	if t.pic == nil || t.picKey != key {
+		t.pic = pixel.MakePictureData(bounds)
+		t.picKey = key
+
+		amp := float64(zoneContrasts[t.contrast]) / 100
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				v := 0.5 + 0.5*amp*t.wave(x, y, width, height)
+				g := uint8(math.Round(v * 255))
+				t.pic.Pix[y*width+x] = core.AdjustBrightness(color.RGBA{g, g, g, 255}, ctx.Brightness)
+			}
+		}
+	}
+
+	sprite := pixel.NewSprite(t.pic, bounds)
+	sprite.Draw(ctx.Win, pixel.IM.Moved(bounds.Center()))
+}
+
+// wave returns the pattern value in -1..1 at pixel (x, y). Zone plates use a
+// quadratic phase, so the local frequency grows linearly from zero at the
+// origin to t.freq at the screen edge.
+func (t *ZonePlate) wave(x, y, width, height int) float64 {
+	switch t.modes[t.mode] {
+	case "circular":
+		dx := float64(x) - float64(width)/2
+		dy := float64(y) - float64(height)/2
+		rmax := math.Hypot(float64(width)/2, float64(height)/2)
+		return math.Cos(math.Pi * t.freq * (dx*dx + dy*dy) / rmax)
+
+	case "horizontal":
+		fx := float64(x)
+		return math.Cos(math.Pi * t.freq * fx * fx / float64(width))
+
+	case "vertical":
+		fy := float64(height - 1 - y)
+		return math.Cos(math.Pi * t.freq * fy * fy / float64(height))
+
+	default: // moire lines
+		// Hard-edged lines, one pixel wide at 0.5 cycles/px, at a fixed tilt.
+		theta := zoneAngles[t.angleIndex] * math.Pi / 180
+		d := float64(x)*math.Sin(theta) + float64(y)*math.Cos(theta)
+		if math.Mod(d*t.freq, 1) < 0.5 {
+			return 1
+		}
+		return -1
+	}
+}
+
+func init() {
+	core.RegisterTest(&ZonePlate{
+		modes:    []string{"circular", "horizontal", "vertical", "moire lines"},
+		freq:     0.5,
+		minFreq:  0.05,
+		maxFreq:  0.5,
+		freqStep: 0.05,
+	})
+}
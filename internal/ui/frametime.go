@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/keshon/screen-tester/internal/core"
+	"github.com/keshon/screen-tester/internal/frametime"
+)
+
+// DrawFrameTiming draws the frame interval graph, histogram and summary in
+// the bottom-right corner.
+func DrawFrameTiming(ctx *core.WindowContext) {
+	rec := ctx.Frames
+	if rec == nil {
+		return
+	}
+	stats := rec.Stats()
+	period := rec.Period()
+
+	boxW, boxH := 520.0, 230.0
+	margin := 10.0
+	bounds := ctx.Win.Bounds()
+	origin := pixel.V(bounds.W()-boxW-margin, margin)
+
+	imd := imdraw.New(nil)
+	imd.Color = color.RGBA{0, 0, 0, 255}
+	imd.Push(origin, origin.Add(pixel.V(boxW, boxH)))
+	imd.Rectangle(0)
+
+	// Interval graph: one column per frame, scaled so the nominal period
+	// sits at a third of the graph height.
+	graph := pixel.R(origin.X+10, origin.Y+10, origin.X+350, origin.Y+130)
+	imd.Color = color.RGBA{40, 40, 40, 255}
+	imd.Push(graph.Min, graph.Max)
+	imd.Rectangle(0)
+
+	intervals := rec.Intervals()
+	if period > 0 && len(intervals) > 0 {
+		colW := graph.W() / float64(len(intervals))
+		for i, d := range intervals {
+			h := core.Clamp(float64(d)/float64(period)/3*graph.H(), 1, graph.H())
+			imd.Color = colornames.Lime
+			if d > period*3/2 {
+				imd.Color = colornames.Red
+			}
+			x := graph.Min.X + float64(i)*colW
+			imd.Push(pixel.V(x, graph.Min.Y), pixel.V(x+colW, graph.Min.Y+h))
+			imd.Rectangle(0)
+		}
+		imd.Color = colornames.White
+		y := graph.Min.Y + graph.H()/3
+		imd.Push(pixel.V(graph.Min.X, y), pixel.V(graph.Max.X, y))
+		imd.Line(1)
+	}
+
+	// Histogram in quarter-period buckets; the bucket holding exactly one
+	// period is the one a healthy display fills.
+	hist := pixel.R(origin.X+360, origin.Y+10, origin.X+boxW-10, origin.Y+130)
+	imd.Color = color.RGBA{40, 40, 40, 255}
+	imd.Push(hist.Min, hist.Max)
+	imd.Rectangle(0)
+	if stats.Samples > 0 {
+		binW := hist.W() / frametime.HistogramBins
+		for i, n := range stats.Histogram {
+			h := hist.H() * float64(n) / float64(stats.Samples)
+			imd.Color = colornames.Skyblue
+			if i == 4 {
+				imd.Color = colornames.Lime
+			}
+			x := hist.Min.X + float64(i)*binW
+			imd.Push(pixel.V(x+1, hist.Min.Y), pixel.V(x+binW-1, hist.Min.Y+h))
+			imd.Rectangle(0)
+		}
+	}
+	imd.Draw(ctx.Win)
+
+	vsync := "off"
+	if ctx.Win.VSync() {
+		vsync = "on"
+	}
+	lines := []string{
+		fmt.Sprintf("Measured: %.2f Hz (display %.0f Hz)  VSync: %s", stats.MeasuredHz, rec.Nominal, vsync),
+		fmt.Sprintf("Frame time: %.2f ms  jitter %.2f ms", Millis(stats.Mean), Millis(stats.Jitter)),
+		fmt.Sprintf("Min / max: %.2f / %.2f ms", Millis(stats.Min), Millis(stats.Max)),
+		fmt.Sprintf("Dropped: %d frames in %d intervals", stats.Dropped, stats.Samples),
+		"F2: Toggle timing  F3: Toggle VSync",
+	}
+	txt := text.New(pixel.V(origin.X+10, origin.Y+boxH-20), Atlas)
+	txt.Color = colornames.White
+	for _, line := range lines {
+		fmt.Fprintln(txt, line)
+	}
+	txt.Draw(ctx.Win, pixel.IM)
+}
+
+// Millis converts d to fractional milliseconds for display.
+func Millis(d time.Duration) float64 {
+	return d.Seconds() * 1000
+}
@@ -3,6 +3,8 @@ package ui
 import (
 	"fmt"
 	"image/color"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/faiface/pixel"
@@ -14,12 +16,17 @@ import (
 	"github.com/keshon/screen-tester/internal/version"
 )
 
-func DrawInfo(ctx *core.WindowContext, test core.ScreenTest, opts core.TestOptions, brightness float64) {
+// DrawInfo draws the info box; status lines such as the slideshow progress
+// go right below the test name.
+func DrawInfo(ctx *core.WindowContext, test core.ScreenTest, opts core.TestOptions, brightness float64, status ...string) {
 	lines := []string{
 		fmt.Sprintf("%s", test.Name()),
+	}
+	lines = append(lines, status...)
+	lines = append(lines,
 		fmt.Sprintf("Resolution: %dx%d", ctx.ScreenWidth, ctx.ScreenHeight),
 		fmt.Sprintf("Brightness: %.1f", brightness),
-	}
+	)
 
 	if size, ok := opts.Extra["size"]; ok {
 		lines = append(lines, fmt.Sprintf("Size: %d px", size.(int)))
@@ -33,6 +40,10 @@ func DrawInfo(ctx *core.WindowContext, test core.ScreenTest, opts core.TestOptio
 		lines = append(lines, fmt.Sprintf("Speed: %.0f ms", speed.(time.Duration).Seconds()*1000))
 	}
 
+	for _, key := range extraKeys(opts.Extra) {
+		lines = append(lines, fmt.Sprintf("%s: %s", strings.ToUpper(key[:1])+key[1:], formatOption(opts.Extra[key])))
+	}
+
 	if test.Description() != "" {
 		lines = append(lines, "")
 		lines = append(lines, core.WrapText(test.Description(), 60)...)
@@ -42,6 +53,11 @@ func DrawInfo(ctx *core.WindowContext, test core.ScreenTest, opts core.TestOptio
 	lines = append(lines, "Controls:")
 	lines = append(lines, "Left / Right: Switch tests")
 	lines = append(lines, "F1: Toggle info")
+	lines = append(lines, "F2: Toggle frame timing, F3: Toggle VSync")
+	lines = append(lines, "F4: Mark defects, F6: Toggle defect marks")
+	lines = append(lines, "F5: Start/stop slideshow, P: Pause slideshow")
+	lines = append(lines, "F7: Save session report, F8: Add note")
+	lines = append(lines, "F9: Screenshot, Shift+F9: Screenshot with overlays")
 	lines = append(lines, "ESC: Exit")
 	lines = append(lines, "")
 	lines = append(lines, version.AppFullName)
@@ -81,3 +97,32 @@ func DrawInfo(ctx *core.WindowContext, test core.ScreenTest, opts core.TestOptio
 		txt.Draw(ctx.Win, pixel.IM)
 	}
 }
+
+// extraKeys returns the option keys that have no dedicated line above, sorted
+// so the overlay does not reorder between frames.
+func extraKeys(extra map[string]interface{}) []string {
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		switch key {
+		case "size", "direction", "speed":
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatOption(v interface{}) string {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return "on"
+		}
+		return "off"
+	case float64:
+		return fmt.Sprintf("%.2f", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
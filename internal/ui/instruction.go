@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/keshon/screen-tester/internal/core"
+)
+
+// DrawInstruction draws an operator instruction centred at the bottom of the
+// screen. Unlike the info box it stays visible when info is toggled off.
+func DrawInstruction(ctx *core.WindowContext, instruction string) {
+	if instruction == "" {
+		return
+	}
+	lines := core.WrapText(instruction, 80)
+
+	lineHeight := 14.0
+	padding := 12.0
+	scale := 2.0
+
+	var textW float64
+	for _, line := range lines {
+		txt := text.New(pixel.ZV, Atlas)
+		fmt.Fprint(txt, line)
+		if w := txt.Bounds().W() * scale; w > textW {
+			textW = w
+		}
+	}
+	boxW := textW + 2*padding
+	boxH := float64(len(lines))*lineHeight*scale + 2*padding
+
+	bounds := ctx.Win.Bounds()
+	origin := pixel.V((bounds.W()-boxW)/2, 20)
+
+	imd := imdraw.New(nil)
+	imd.Color = color.RGBA{0, 0, 0, 255}
+	imd.Push(origin, origin.Add(pixel.V(boxW, boxH)))
+	imd.Rectangle(0)
+	imd.Color = colornames.Yellow
+	imd.Push(origin, origin.Add(pixel.V(boxW, boxH)))
+	imd.Rectangle(2)
+	imd.Draw(ctx.Win)
+
+	for i, line := range lines {
+		txt := text.New(pixel.ZV, Atlas)
+		txt.Color = colornames.Yellow
+		fmt.Fprint(txt, line)
+		y := origin.Y + boxH - padding - lineHeight*scale*float64(i+1) + 4
+		txt.Draw(ctx.Win, pixel.IM.Scaled(pixel.ZV, scale).Moved(pixel.V(origin.X+padding, y)))
+	}
+}